@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/go-chi/chi"
+)
+
+// accountContextKey is The Request Context Key of The Account Acted on
+type accountContextKey struct{}
+
+type reqWhatsAppAccount struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
+}
+
+type resWhatsAppAccount struct {
+	Status  bool        `json:"status"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    hlp.Account `json:"data"`
+}
+
+type resWhatsAppAccounts struct {
+	Status  bool          `json:"status"`
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    []hlp.Account `json:"data"`
+}
+
+// AccountScope Function as Middleware Resolving The Account of a Request,
+// The Account Path Parameter or Else The Account Named After The User
+func AccountScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+		if err != nil {
+			svc.ResponseInternalError(w, err.Error())
+			return
+		}
+
+		account := chi.URLParam(r, "account")
+		if len(account) == 0 {
+			account = user
+		}
+
+		allowed, err := hlp.WAAccountAllowed(user, account)
+		if err != nil {
+			responseError(w, err)
+			return
+		}
+
+		if !allowed {
+			svc.Log("warn", "http-access", "user "+user+" denied access to account "+account+" at URI "+r.RequestURI)
+			svc.ResponseError(w, http.StatusForbidden, "forbidden", "no access to account "+account)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), accountContextKey{}, account)))
+	})
+}
+
+// requestAccount Function Returns The Account Resolved by AccountScope
+func requestAccount(r *http.Request) string {
+	account, _ := r.Context().Value(accountContextKey{}).(string)
+
+	return account
+}
+
+func WhatsAppGetAccounts(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	accounts, err := hlp.WAAccountList(user)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppAccounts
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = accounts
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppCreateAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	var reqBody reqWhatsAppAccount
+
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	account, err := hlp.WAAccountCreate(user, hlp.Account{ID: reqBody.ID, Name: reqBody.Name, Users: reqBody.Users})
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseAccount(w, http.StatusCreated, "Created", account)
+}
+
+func WhatsAppGetAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	account, err := hlp.WAAccountGet(user, requestAccount(r))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseAccount(w, http.StatusOK, "Success", account)
+}
+
+func WhatsAppDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	err = hlp.WAAccountDelete(user, requestAccount(r))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	svc.ResponseSuccess(w, "")
+}
+
+func WhatsAppGrantAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	account, err := hlp.WAAccountGrant(user, requestAccount(r), chi.URLParam(r, "user"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseAccount(w, http.StatusOK, "Success", account)
+}
+
+func WhatsAppRevokeAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+	if err != nil {
+		svc.ResponseInternalError(w, err.Error())
+		return
+	}
+
+	account, err := hlp.WAAccountRevoke(user, requestAccount(r), chi.URLParam(r, "user"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseAccount(w, http.StatusOK, "Success", account)
+}
+
+// responseAccount Function Writes an Account
+func responseAccount(w http.ResponseWriter, code int, message string, account hlp.Account) {
+	var response resWhatsAppAccount
+
+	response.Status = true
+	response.Code = code
+	response.Message = message
+	response.Data = account
+
+	svc.ResponseWrite(w, response.Code, response)
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+)
+
+func TestWhatsAppCreateAccountReservedUsername(t *testing.T) {
+	srv := testServer(t)
+	testUser(t, srv, "reserved")
+	token := testUser(t, srv, "squatter")
+
+	code := testRequest(t, srv, http.MethodPost, "/accounts", token, `{"id":"reserved"}`, nil)
+	if code != http.StatusConflict {
+		t.Fatalf("creating the account of another user answered %d", code)
+	}
+
+	code = testRequest(t, srv, http.MethodPost, "/accounts", token, `{"id":"squatter-shop"}`, nil)
+	if code != http.StatusCreated {
+		t.Fatalf("creating an account answered %d", code)
+	}
+}
+
+func TestWhatsAppDefaultAccount(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "defaulted")
+	other := testUser(t, srv, "neighbour")
+
+	var accounts []hlp.Account
+
+	testRequest(t, srv, http.MethodGet, "/accounts", token, "", &accounts)
+	if len(accounts) != 0 {
+		t.Fatalf("user has accounts before using one: %+v", accounts)
+	}
+
+	// Using The Account Named After The User Creates it
+	code := testRequest(t, srv, http.MethodGet, "/scheduled", token, "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("scheduled answered %d", code)
+	}
+
+	testRequest(t, srv, http.MethodGet, "/accounts", token, "", &accounts)
+	if len(accounts) != 1 || accounts[0].ID != "defaulted" || len(accounts[0].Users) != 1 || accounts[0].Users[0] != "defaulted" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+
+	code = testRequest(t, srv, http.MethodGet, "/accounts/defaulted", other, "", nil)
+	if code != http.StatusForbidden {
+		t.Fatalf("another user reading the account answered %d", code)
+	}
+}
+
+func TestWhatsAppGetAttachmentOfOtherAccount(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "attached")
+	other := testUser(t, srv, "prying")
+
+	code := testRequest(t, srv, http.MethodGet, "/accounts/attached/messages/UNKNOWN/data", token, "", nil)
+	if code != http.StatusNotFound {
+		t.Fatalf("unknown attachment answered %d", code)
+	}
+
+	code = testRequest(t, srv, http.MethodGet, "/accounts/attached/messages/UNKNOWN/data", other, "", nil)
+	if code != http.StatusForbidden {
+		t.Errorf("attachment of another account answered %d", code)
+	}
+}
@@ -17,12 +17,14 @@ func GetAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if reqBody.Password != svc.Config.GetString("AUTH_PASSWORD") {
+	user, err := svc.UserAuthenticate(reqBody.Username, reqBody.Password)
+	if err != nil {
+		svc.Log("warn", "http-access", "failed authorization of user "+reqBody.Username)
 		svc.ResponseBadRequest(w, "invalid authorization")
 		return
 	}
 
-	token, err := svc.GetJWTToken(reqBody.Username)
+	token, err := svc.GetJWTToken(user)
 	if err != nil {
 		svc.ResponseInternalError(w, err.Error())
 		return
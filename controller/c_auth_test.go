@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+func TestGetAuthEscapedPassword(t *testing.T) {
+	srv := testServer(t)
+
+	password := `quo"te\back:slash`
+
+	_, err := svc.UserCreate("escaped", password, svc.UserRoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := testToken(t, srv, "escaped", password)
+
+	code := testRequest(t, srv, http.MethodGet, "/search?q=hello", token, "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("token of the user answered %d", code)
+	}
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/go-chi/chi"
+)
+
+type resWhatsAppChats struct {
+	Status  bool       `json:"status"`
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    []hlp.Chat `json:"data"`
+}
+
+type resWhatsAppChatMessages struct {
+	Status  bool            `json:"status"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    hlp.HistoryPage `json:"data"`
+}
+
+func WhatsAppGetChats(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var response resWhatsAppChats
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = hlp.WAChatList(jid)
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppGetChatMessages(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	query, err := historyQuery(r)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	page, err := hlp.WAHistory(jid, chi.URLParam(r, "chatID"), query)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppChatMessages
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = page
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+// historyQuery Function Parses The Cursor, Limit and Time Range of a
+// Request Listing Stored Messages
+func historyQuery(r *http.Request) (hlp.HistoryQuery, error) {
+	var err error
+
+	params := r.URL.Query()
+	query := hlp.HistoryQuery{Cursor: params.Get("cursor")}
+
+	if since := params.Get("since"); len(since) != 0 {
+		query.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, errors.New("since must be an RFC3339 time")
+		}
+	}
+
+	if until := params.Get("until"); len(until) != 0 {
+		query.Until, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return query, errors.New("until must be an RFC3339 time")
+		}
+	}
+
+	if limit := params.Get("limit"); len(limit) != 0 {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, errors.New("limit must be a number")
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+type reqWhatsAppContactCheck struct {
+	Numbers []string `json:"numbers"`
+}
+
+type resWhatsAppContacts struct {
+	Status  bool          `json:"status"`
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    []hlp.Contact `json:"data"`
+}
+
+type resWhatsAppContactCheck struct {
+	Status  bool               `json:"status"`
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Data    []hlp.ContactCheck `json:"data"`
+}
+
+func WhatsAppGetContacts(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var response resWhatsAppContacts
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = hlp.WAContactList(jid)
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppCheckContacts(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var reqBody reqWhatsAppContactCheck
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	if len(reqBody.Numbers) == 0 {
+		svc.ResponseBadRequest(w, "numbers are required")
+		return
+	}
+
+	checks, err := hlp.WAContactCheck(jid, reqBody.Numbers)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppContactCheck
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = checks
+
+	svc.ResponseWrite(w, response.Code, response)
+}
@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+// responseError Function Writes an Error Returned by The Helpers or The
+// User Store Using The HTTP Status and Error Code Describing it
+func responseError(w http.ResponseWriter, err error) {
+	code, errorCode, ok := userErrorCode(err)
+	if !ok {
+		code, errorCode = hlp.WAErrorCode(err)
+	}
+
+	svc.ResponseError(w, code, errorCode, err.Error())
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+// eventKeepAlive is How Often a Comment is Sent to Keep Idle Streams Open
+const eventKeepAlive = 30 * time.Second
+
+// WhatsAppGetEvents Function Streams The Events of an Account as Server-Sent
+// Events, Replaying Logged Events After Last-Event-ID First
+func WhatsAppGetEvents(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		svc.ResponseInternalError(w, "streaming is not supported")
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if len(lastEventID) == 0 {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var lastID uint64
+	var err error
+
+	if len(lastEventID) > 0 {
+		lastID, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			svc.ResponseBadRequest(w, "invalid last event id")
+			return
+		}
+	}
+
+	// Subscribe Before Reading The Log so no Event Falls in Between
+	ch, cancel := hlp.WASubscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Replay Logged Events When Resuming or Catching up on Dropped Events
+	backfill := func() error {
+		logged, err := hlp.WAEventsSince(jid, lastID)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range logged {
+			err = writeEvent(w, event)
+			if err != nil {
+				return err
+			}
+
+			lastID = event.ID
+		}
+
+		return nil
+	}
+
+	if len(lastEventID) > 0 {
+		err = backfill()
+		if err != nil {
+			svc.Log("error", "event", err.Error())
+			return
+		}
+	}
+
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if event.JID != jid || (event.ID > 0 && event.ID <= lastID) {
+				continue
+			}
+
+			if event.ID > lastID+1 && lastID > 0 {
+				err = backfill()
+			} else {
+				err = writeEvent(w, event)
+				if event.ID > 0 {
+					lastID = event.ID
+				}
+			}
+		}
+
+		if err != nil {
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// writeEvent Function Writes an Event in The Server-Sent Events Format
+func writeEvent(w http.ResponseWriter, event hlp.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if event.ID > 0 {
+		_, err = fmt.Fprintf(w, "id: %d\n", event.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
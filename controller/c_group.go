@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/go-chi/chi"
+)
+
+type reqWhatsAppGroup struct {
+	Subject      *string  `json:"subject"`
+	Participants []string `json:"participants"`
+}
+
+type reqWhatsAppGroupParticipants struct {
+	Participants []string `json:"participants"`
+}
+
+type resWhatsAppGroup struct {
+	Status  bool      `json:"status"`
+	Code    int       `json:"code"`
+	Message string    `json:"message"`
+	Data    hlp.Group `json:"data"`
+}
+
+type resWhatsAppGroups struct {
+	Status  bool        `json:"status"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    []hlp.Group `json:"data"`
+}
+
+type resWhatsAppGroupParticipants struct {
+	Status  bool                         `json:"status"`
+	Code    int                          `json:"code"`
+	Message string                       `json:"message"`
+	Data    []hlp.GroupParticipantResult `json:"data"`
+}
+
+type resWhatsAppGroupInvite struct {
+	Status  bool   `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Link string `json:"link"`
+	} `json:"data"`
+}
+
+func WhatsAppGetGroups(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	groups, err := hlp.WAGroupList(jid)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppGroups
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = groups
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppCreateGroup(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var reqBody reqWhatsAppGroup
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	if reqBody.Subject == nil {
+		svc.ResponseBadRequest(w, "subject is required")
+		return
+	}
+
+	group, err := hlp.WAGroupCreate(jid, *reqBody.Subject, reqBody.Participants)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseGroup(w, http.StatusCreated, group)
+}
+
+func WhatsAppGetGroup(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	group, err := hlp.WAGroupGet(jid, chi.URLParam(r, "groupID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseGroup(w, http.StatusOK, group)
+}
+
+func WhatsAppUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var reqBody reqWhatsAppGroup
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	// The Subject is The Only Thing The WhatsApp Library Can Change
+	if reqBody.Subject == nil {
+		svc.ResponseBadRequest(w, "subject is required")
+		return
+	}
+
+	groupID := chi.URLParam(r, "groupID")
+
+	err = hlp.WAGroupSubject(jid, groupID, *reqBody.Subject)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	group, err := hlp.WAGroupGet(jid, groupID)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseGroup(w, http.StatusOK, group)
+}
+
+func WhatsAppUpdateGroupParticipants(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var reqBody reqWhatsAppGroupParticipants
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	results, err := hlp.WAGroupParticipants(jid, chi.URLParam(r, "groupID"), chi.URLParam(r, "action"), reqBody.Participants)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppGroupParticipants
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = results
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppGetGroupInvite(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	link, err := hlp.WAGroupInviteLink(jid, chi.URLParam(r, "groupID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppGroupInvite
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data.Link = link
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppLeaveGroup(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	err := hlp.WAGroupLeave(jid, chi.URLParam(r, "groupID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	svc.ResponseSuccess(w, "")
+}
+
+func responseGroup(w http.ResponseWriter, code int, group hlp.Group) {
+	var response resWhatsAppGroup
+
+	response.Status = true
+	response.Code = code
+	response.Message = http.StatusText(code)
+	response.Data = group
+
+	svc.ResponseWrite(w, response.Code, response)
+}
@@ -3,9 +3,26 @@ package controller
 import (
 	"net/http"
 
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
 	svc "github.com/theveloped/go-whatsapp-rest/service"
 )
 
+type resHealth struct {
+	Status  bool   `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		Sessions hlp.RestoreSummary `json:"sessions"`
+	} `json:"data"`
+}
+
+type resHealthSessions struct {
+	Status  bool                `json:"status"`
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    []hlp.RestoreResult `json:"data"`
+}
+
 // GetIndex Function to Show API Information
 func GetIndex(w http.ResponseWriter, r *http.Request) {
 	svc.ResponseSuccess(w, "WhatsApp Go Service is running")
@@ -13,5 +30,24 @@ func GetIndex(w http.ResponseWriter, r *http.Request) {
 
 // GetHealth Function to Show Health Check Status
 func GetHealth(w http.ResponseWriter, r *http.Request) {
-	svc.HealthCheck(w)
+	var response resHealth
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data.Sessions = hlp.WARestoreSummary()
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+// GetHealthSessions Function to Show The Startup Restore Result of Every Session
+func GetHealthSessions(w http.ResponseWriter, r *http.Request) {
+	var response resHealthSessions
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = hlp.WARestoreResults()
+
+	svc.ResponseWrite(w, response.Code, response)
 }
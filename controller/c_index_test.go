@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+)
+
+func TestGetHealth(t *testing.T) {
+	srv := testServer(t)
+
+	var health struct {
+		Sessions hlp.RestoreSummary `json:"sessions"`
+	}
+
+	code := testRequest(t, srv, http.MethodGet, "/health", "", "", &health)
+	if code != http.StatusOK {
+		t.Fatalf("health answered %d", code)
+	}
+
+	token := testUser(t, srv, "health")
+
+	code = testRequest(t, srv, http.MethodGet, "/health/sessions", token, "", nil)
+	if code != http.StatusForbidden {
+		t.Errorf("sessions answered %d to a user, want %d", code, http.StatusForbidden)
+	}
+
+	var sessions []hlp.RestoreResult
+
+	code = testRequest(t, srv, http.MethodGet, "/health/sessions", testToken(t, srv, "admin", testPassword), "", &sessions)
+	if code != http.StatusOK {
+		t.Errorf("sessions answered %d to an admin, want %d", code, http.StatusOK)
+	}
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+func WhatsAppSearch(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	q := r.URL.Query().Get("q")
+	if len(q) == 0 {
+		svc.ResponseBadRequest(w, "q is required")
+		return
+	}
+
+	query, err := historyQuery(r)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	page, err := hlp.WASearch(jid, q, r.URL.Query().Get("chat"), query)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppChatMessages
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = page
+
+	svc.ResponseWrite(w, response.Code, response)
+}
@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+
+	"github.com/go-chi/chi"
+)
+
+// testPassword is The Password of Every User Created by The Tests
+const testPassword = "test-password"
+
+// testClients Keeps Every Fake Client Created Through hlp.NewClient
+var testClients = struct {
+	sync.Mutex
+	list []*wafake.Client
+}{}
+
+// TestMain Function Initializes The Service Against a Temporary Store,
+// Generated RSA Keys and Fake WhatsApp Clients
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "go-whatsapp-rest")
+	if err != nil {
+		panic(err)
+	}
+
+	err = testKeys(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, "uploads"), 0755)
+
+	os.Setenv("CONFIG_ENV", "dev")
+	os.Setenv("CONFIG_FILE_PATH", "../configs")
+	os.Setenv("CONFIG_LOG_LEVEL", "error")
+	os.Setenv("DEV_SERVER_STORE_PATH", filepath.Join(dir, "stores"))
+	os.Setenv("DEV_SERVER_UPLOAD_PATH", filepath.Join(dir, "uploads"))
+	os.Setenv("DEV_CRYPT_PRIVATE_KEY_FILE", filepath.Join(dir, "key.pem"))
+	os.Setenv("DEV_CRYPT_PUBLIC_KEY_FILE", filepath.Join(dir, "key.pub"))
+	os.Setenv("DEV_AUTH_PASSWORD", testPassword)
+
+	svc.Initialize()
+
+	hlp.NewClient = func(timeout int) (hlp.Client, error) {
+		client := wafake.NewClient()
+
+		testClients.Lock()
+		testClients.list = append(testClients.list, client)
+		testClients.Unlock()
+
+		return client, nil
+	}
+
+	code := m.Run()
+
+	svc.Store.Close()
+	os.RemoveAll(dir)
+
+	os.Exit(code)
+}
+
+// testKeys Function Writes an RSA Key Pair as key.pem and key.pub
+func testKeys(dir string) error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, "key.pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}), 0644)
+}
+
+// testServer Function Starts a Server Routing The User and Account Endpoints
+// The Way route.go Does Without a Base Path
+func testServer(t *testing.T) *httptest.Server {
+	r := chi.NewRouter()
+
+	r.Get("/health", GetHealth)
+	r.With(svc.AuthJWT, svc.AuthAdmin).Get("/health/sessions", GetHealthSessions)
+	r.With(svc.AuthBasic).Get("/auth", GetAuth)
+
+	r.Route("/users", func(r chi.Router) {
+		r.Use(svc.AuthJWT, svc.AuthAdmin)
+
+		r.Delete("/{username}", DeleteUser)
+	})
+
+	r.Route("/accounts", func(r chi.Router) {
+		r.With(svc.AuthJWT).Get("/", WhatsAppGetAccounts)
+		r.With(svc.AuthJWT).Post("/", WhatsAppCreateAccount)
+
+		r.Route("/{account}", func(r chi.Router) {
+			r.Use(svc.AuthJWT, AccountScope)
+
+			r.Get("/", WhatsAppGetAccount)
+			r.Get("/messages/{messageID}/data", WhatsAppGetAttachment)
+		})
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(svc.AuthJWT, AccountScope)
+
+		r.Post("/login", WhatsAppLogin)
+		r.Get("/login/{loginID}", WhatsAppGetLogin)
+		r.Post("/messagetext", WhatsAppSendMessage)
+		r.Post("/messages", WhatsAppSendGeneric)
+		r.Get("/messages/{messageID}", WhatsAppGetMessage)
+		r.Get("/scheduled", WhatsAppGetScheduled)
+		r.Delete("/scheduled/{messageID}", WhatsAppCancelScheduled)
+		r.Post("/logout", WhatsAppLogout)
+		r.Get("/sessions/{account}", WhatsAppGetSession)
+		r.Get("/chats/{chatID}/messages", WhatsAppGetChatMessages)
+		r.Get("/search", WhatsAppSearch)
+	})
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// testUser Function Creates a User and Returns a Token of it
+func testUser(t *testing.T, srv *httptest.Server, username string) string {
+	_, err := svc.UserCreate(username, testPassword, svc.UserRoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testToken(t, srv, username, testPassword)
+}
+
+// testToken Function Logs a User in Through GET /auth
+func testToken(t *testing.T, srv *httptest.Server, username string, password string) string {
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/auth", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+
+	code, data := testDo(t, req)
+	if code != http.StatusOK {
+		t.Fatalf("login of %s answered %d", username, code)
+	}
+
+	var res struct {
+		Token string `json:"token"`
+	}
+
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.Token
+}
+
+// testLogin Function Logs The Account of a Token in to WhatsApp and
+// Returns The Fake Client Behind it
+func testLogin(t *testing.T, srv *httptest.Server, token string) *wafake.Client {
+	var login hlp.LoginSession
+
+	code := testRequest(t, srv, http.MethodPost, "/login", token, `{"timeout":5}`, &login)
+	if code != http.StatusOK && code != http.StatusAccepted {
+		t.Fatalf("login answered %d", code)
+	}
+
+	testEventually(t, func() bool {
+		testRequest(t, srv, http.MethodGet, "/login/"+login.ID, token, "", &login)
+		return login.Status == hlp.LoginSuccess
+	})
+
+	testClients.Lock()
+	defer testClients.Unlock()
+
+	return testClients.list[len(testClients.list)-1]
+}
+
+// testRequest Function Sends a Request and Decodes The Data of The Response
+func testRequest(t *testing.T, srv *httptest.Server, method string, path string, token string, body string, data interface{}) int {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	code, raw := testDo(t, req)
+	if data != nil && code < http.StatusBadRequest {
+		err = json.Unmarshal(raw, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return code
+}
+
+// testDo Function Sends a Request and Returns The Status and The Data
+// of The Response
+func testDo(t *testing.T, req *http.Request) (int, json.RawMessage) {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	_ = json.NewDecoder(res.Body).Decode(&body)
+
+	return res.StatusCode, body.Data
+}
+
+// testEventually Function Waits For a Condition to Hold
+func testEventually(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+}
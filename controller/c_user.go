@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/go-chi/chi"
+)
+
+// userErrorCodes Maps The Errors of The User Store to Their HTTP Status
+// and Machine-Readable Code
+var userErrorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{svc.ErrUserNotFound, http.StatusNotFound, "not_found"},
+	{svc.ErrUserExists, http.StatusConflict, "conflict"},
+	{svc.ErrUserLastAdmin, http.StatusConflict, "conflict"},
+	{svc.ErrUserInvalid, http.StatusBadRequest, "invalid_argument"},
+}
+
+type reqUser struct {
+	Username string  `json:"username"`
+	Password *string `json:"password"`
+	Role     *string `json:"role"`
+	Disabled *bool   `json:"disabled"`
+}
+
+type resUser struct {
+	Status  bool     `json:"status"`
+	Code    int      `json:"code"`
+	Message string   `json:"message"`
+	Data    svc.User `json:"data"`
+}
+
+type resUsers struct {
+	Status  bool       `json:"status"`
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    []svc.User `json:"data"`
+}
+
+func GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := svc.UserList()
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resUsers
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = users
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := svc.UserGet(chi.URLParam(r, "username"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseUser(w, http.StatusOK, "Success", user)
+}
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	var reqBody reqUser
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	if reqBody.Password == nil {
+		svc.ResponseBadRequest(w, "password is required")
+		return
+	}
+
+	var role string
+	if reqBody.Role != nil {
+		role = *reqBody.Role
+	}
+
+	user, err := svc.UserCreate(reqBody.Username, *reqBody.Password, role)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	if reqBody.Disabled != nil && *reqBody.Disabled {
+		user, err = svc.UserChange(user.Username, svc.UserUpdate{Disabled: reqBody.Disabled})
+		if err != nil {
+			responseError(w, err)
+			return
+		}
+	}
+
+	responseUser(w, http.StatusCreated, "Created", user)
+}
+
+func UpdateUser(w http.ResponseWriter, r *http.Request) {
+	var reqBody reqUser
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	user, err := svc.UserChange(chi.URLParam(r, "username"), svc.UserUpdate{
+		Password: reqBody.Password,
+		Role:     reqBody.Role,
+		Disabled: reqBody.Disabled,
+	})
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseUser(w, http.StatusOK, "Success", user)
+}
+
+func DeleteUser(w http.ResponseWriter, r *http.Request) {
+	err := hlp.WAUserDelete(chi.URLParam(r, "username"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	svc.ResponseSuccess(w, "")
+}
+
+// responseUser Function Writes a User
+func responseUser(w http.ResponseWriter, code int, message string, user svc.User) {
+	var response resUser
+
+	response.Status = true
+	response.Code = code
+	response.Message = message
+	response.Data = user
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+// userErrorCode Function Returns The HTTP Status and Code Describing an
+// Error of The User Store, Reporting Whether it is One
+func userErrorCode(err error) (int, string, bool) {
+	for _, c := range userErrorCodes {
+		if errors.Is(err, c.err) {
+			return c.status, c.code, true
+		}
+	}
+
+	return 0, "", false
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+func TestDeleteUser(t *testing.T) {
+	srv := testServer(t)
+
+	_, err := svc.UserCreate("deleting-admin", testPassword, svc.UserRoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := testToken(t, srv, "deleting-admin", testPassword)
+	token := testUser(t, srv, "departing")
+	other := testUser(t, srv, "remaining")
+
+	// The Account Named After The User And an Account Shared With Another User
+	code := testRequest(t, srv, http.MethodGet, "/scheduled", token, "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("scheduled answered %d", code)
+	}
+
+	code = testRequest(t, srv, http.MethodPost, "/accounts", token, `{"id":"departing-team","users":["remaining"]}`, nil)
+	if code != http.StatusCreated {
+		t.Fatalf("creating an account answered %d", code)
+	}
+
+	code = testRequest(t, srv, http.MethodDelete, "/users/departing", admin, "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("deleting the user answered %d", code)
+	}
+
+	code = testRequest(t, srv, http.MethodDelete, "/users/departing", admin, "", nil)
+	if code != http.StatusNotFound {
+		t.Fatalf("deleting the user again answered %d", code)
+	}
+
+	// A User Created Again Under The Same Name Inherits Nothing
+	again := testUser(t, srv, "departing")
+
+	code = testRequest(t, srv, http.MethodGet, "/accounts", token, "", nil)
+	if code != http.StatusUnauthorized {
+		t.Fatalf("token of the deleted user answered %d", code)
+	}
+
+	var accounts []hlp.Account
+
+	testRequest(t, srv, http.MethodGet, "/accounts", again, "", &accounts)
+	if len(accounts) != 0 {
+		t.Fatalf("user created again has accounts %+v", accounts)
+	}
+
+	code = testRequest(t, srv, http.MethodGet, "/accounts/departing-team", again, "", nil)
+	if code != http.StatusForbidden {
+		t.Fatalf("shared account of the deleted user answered %d", code)
+	}
+
+	var account hlp.Account
+
+	testRequest(t, srv, http.MethodGet, "/accounts/departing-team", other, "", &account)
+	if len(account.Users) != 1 || account.Users[0] != "remaining" {
+		t.Fatalf("unexpected users of the shared account %+v", account.Users)
+	}
+
+	// The Account Named After The Deleted User Was Retired, so Using it
+	// Creates it Anew
+	testRequest(t, srv, http.MethodGet, "/scheduled", again, "", nil)
+	testRequest(t, srv, http.MethodGet, "/accounts", again, "", &accounts)
+	if len(accounts) != 1 || accounts[0].ID != "departing" || len(accounts[0].Users) != 1 {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+}
@@ -2,22 +2,25 @@ package controller
 
 import (
 	"encoding/json"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"fmt"
+	"time"
 
 	hlp "github.com/theveloped/go-whatsapp-rest/helper"
 	svc "github.com/theveloped/go-whatsapp-rest/service"
 
 	"github.com/go-chi/chi"
-	"path/filepath"
 )
 
 type reqWhatsAppLogin struct {
-	Output  string `json:"output"`
-	Timeout int    `json:"timeout"`
-	Webhook  string `json:"webhook"`
+	Output        string `json:"output"`
+	Timeout       int    `json:"timeout"`
+	Webhook       string `json:"webhook"`
+	WebhookSecret string `json:"webhook_secret"`
 }
 
 type resWhatsAppLogin struct {
@@ -25,24 +28,79 @@ type resWhatsAppLogin struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 	Data    struct {
-		QRCode  string `json:"qrcode"`
-		Timeout int    `json:"timeout"`
+		hlp.LoginSession
+		Timeout int `json:"timeout,omitempty"`
 	} `json:"data"`
 }
 
+type resWhatsAppSession struct {
+	Status  bool            `json:"status"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    hlp.SessionInfo `json:"data"`
+}
+
+type resWhatsAppMessage struct {
+	Status  bool             `json:"status"`
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Data    hlp.QueueMessage `json:"data"`
+}
+
+type resWhatsAppMessages struct {
+	Status  bool               `json:"status"`
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Data    []hlp.QueueMessage `json:"data"`
+}
+
 type reqWhatsAppSendMessage struct {
-	MSISDN  string `json:"msisdn"`
-	Message string `json:"message"`
-	Delay   int    `json:"delay"`
+	Type         string   `json:"type"`
+	MSISDN       string   `json:"msisdn"`
+	Message      string   `json:"message"`
+	Delay        int      `json:"delay"`
+	SendAt       string   `json:"send_at"`
+	Lat          *float64 `json:"lat"`
+	Lng          *float64 `json:"lng"`
+	Name         string   `json:"name"`
+	Address      string   `json:"address"`
+	VCard        string   `json:"vcard"`
+	Phone        string   `json:"phone"`
+	Organization string   `json:"organization"`
+	Email        string   `json:"email"`
+
+	QuotedMessageID   string `json:"quoted_message_id"`
+	QuotedText        string `json:"quoted_text"`
+	QuotedParticipant string `json:"quoted_participant"`
 }
 
-func WhatsAppLogin(w http.ResponseWriter, r *http.Request) {
-	jid, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+// sendAt Method Returns When The Message Should be Sent
+// An Absolute send_at Takes Precedence Over a Relative delay in Seconds
+func (req reqWhatsAppSendMessage) sendAt() (time.Time, error) {
+	if len(req.SendAt) != 0 {
+		return time.Parse(time.RFC3339, req.SendAt)
+	}
+
+	return time.Now().Add(time.Duration(req.Delay) * time.Second), nil
+}
+
+// quote Method Fills The Quoted Message of msg so The Reply Threads Correctly
+func (req reqWhatsAppSendMessage) quote(jid string, msg *hlp.QueueMessage) error {
+	text, participant, err := hlp.WAQuoteResolve(jid, msg.To, req.QuotedMessageID, req.QuotedText, req.QuotedParticipant)
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
-		return
+		return err
 	}
 
+	msg.QuotedID = req.QuotedMessageID
+	msg.QuotedText = text
+	msg.QuotedParticipant = participant
+
+	return nil
+}
+
+func WhatsAppLogin(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
 	var reqBody reqWhatsAppLogin
 	_ = json.NewDecoder(r.Body).Decode(&reqBody)
 
@@ -54,46 +112,45 @@ func WhatsAppLogin(w http.ResponseWriter, r *http.Request) {
 		reqBody.Timeout = 10
 	}
 
-	err = hlp.WAInit(jid, reqBody.Timeout)
-	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+	output := strings.ToLower(reqBody.Output)
+	if output != "json" && output != "html" {
+		svc.ResponseBadRequest(w, "")
 		return
 	}
 
-	file := svc.Config.GetString("SERVER_STORE_PATH") + "/" + jid + ".gob"
-
-	qrstr := make(chan string)
-	errmsg := make(chan error)
-
-	go func() {
-		hlp.WAConnect(jid, reqBody.Webhook, reqBody.Timeout, file, qrstr, errmsg)
-	}()
+	// The Login Response Has no Room For a Generated Secret
+	if len(reqBody.Webhook) > 0 && len(reqBody.WebhookSecret) == 0 {
+		svc.ResponseBadRequest(w, "webhook_secret is required with webhook")
+		return
+	}
 
-	select {
-	case qrcode := <-qrstr:
-		qrcode = "data:image/png;base64," + qrcode
+	if len(reqBody.Webhook) > 0 {
+		_, err := hlp.WAWebhookPut(jid, hlp.WebhookSubscriber{URL: reqBody.Webhook, Secret: reqBody.WebhookSecret})
+		if err != nil {
+			responseError(w, err)
+			return
+		}
+	}
 
-		switch strings.ToLower(reqBody.Output) {
-		case "json":
-			var response resWhatsAppLogin
+	file := hlp.WASessionFile(jid)
 
-			response.Status = true
-			response.Code = 200
-			response.Message = "Success"
-			response.Data.QRCode = qrcode
-			response.Data.Timeout = reqBody.Timeout
+	session := hlp.WALoginStart(jid, file, reqBody.Timeout)
 
-			svc.ResponseWrite(w, response.Code, response)
-		case "html":
-			var response string
+	// Wait For The First QR Code, The Login Keeps Refreshing it Afterwards
+	session, err := hlp.WALoginWait(jid, session.ID, time.Duration(reqBody.Timeout)*time.Second)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
 
-			response = `
+	if output == "html" && len(session.QRCode) > 0 {
+		response := `
         <html>
           <head>
             <title>WhatsApp Login</title>
           </head>
           <body>
-            <img src="` + qrcode + `" />
+            <img src="` + session.QRCode + `" />
             <p>
               <b>QR Code Scan</b>
               <br/>
@@ -103,104 +160,194 @@ func WhatsAppLogin(w http.ResponseWriter, r *http.Request) {
         </html>
       `
 
-			w.Write([]byte(response))
-		default:
-			svc.ResponseBadRequest(w, "")
-		}
-	case err := <-errmsg:
-		if len(err.Error()) != 0 {
-			svc.ResponseInternalError(w, err.Error())
-			return
-		}
+		w.Write([]byte(response))
+		return
+	}
+
+	responseLogin(w, session, reqBody.Timeout)
+}
 
-		svc.ResponseSuccess(w, "")
+func WhatsAppGetLogin(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	session, err := hlp.WALoginGet(jid, chi.URLParam(r, "loginID"))
+	if err != nil {
+		responseError(w, err)
+		return
 	}
+
+	responseLogin(w, session, 0)
 }
 
-func WhatsAppLogout(w http.ResponseWriter, r *http.Request) {
-	jid, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+func WhatsAppCancelLogin(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	session, err := hlp.WALoginCancel(jid, chi.URLParam(r, "loginID"))
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+		responseError(w, err)
 		return
 	}
 
-	file := svc.Config.GetString("SERVER_STORE_PATH") + "/" + jid + ".gob"
+	responseLogin(w, session, 0)
+}
 
-	err = hlp.WASessionLogout(jid, file)
+// responseLogin Function Writes a Login Session, Accepted While it is
+// Still Waiting For its First QR Code
+func responseLogin(w http.ResponseWriter, session hlp.LoginSession, timeout int) {
+	var response resWhatsAppLogin
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data.LoginSession = session
+	response.Data.Timeout = timeout
+
+	if session.Status == hlp.LoginPending && len(session.QRCode) == 0 {
+		response.Code = http.StatusAccepted
+		response.Message = "Accepted"
+	}
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppLogout(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	file := hlp.WASessionFile(jid)
+
+	err := hlp.WASessionLogout(jid, file)
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+		responseError(w, err)
 		return
 	}
 
 	svc.ResponseSuccess(w, "")
 }
 
-func WhatsAppGetAttachment(w http.ResponseWriter, r *http.Request) {
-	messageID := chi.URLParam(r, "messageID")
+func WhatsAppGetSession(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	session, ok := hlp.WASessionInfo(jid)
+	if !ok {
+		svc.ResponseNotFound(w, "session not found")
+		return
+	}
+
+	var response resWhatsAppSession
 
-  	if len(messageID) == 0 {
-    	http.Error(w, http.StatusText(422), 422)
-    	return
-  	}
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = session
 
-  	pattern := fmt.Sprintf("%v/%v.*", svc.Config.GetString("SERVER_UPLOAD_PATH"), messageID)
-  	matches, err := filepath.Glob(pattern)
+	svc.ResponseWrite(w, response.Code, response)
+}
 
-    if err != nil {
-    	http.Error(w, http.StatusText(404), 404)
-        return
-    }
+func WhatsAppGetAttachment(w http.ResponseWriter, r *http.Request) {
+	file, mediaType, err := hlp.WAHistoryMedia(requestAccount(r), chi.URLParam(r, "messageID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
 
-    if len(matches) == 0 {
-    	http.Error(w, http.StatusText(404), 404)
-        return
-    }
+	if len(mediaType) > 0 {
+		w.Header().Set("Content-Type", mediaType)
+	}
 
-    http.ServeFile(w, r, matches[0])
+	http.ServeFile(w, r, file)
 }
 
 func WhatsAppSendGeneric(w http.ResponseWriter, r *http.Request) {
-	contentType := r.Header.Get("Content-Type")
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
 	if contentType == "application/json" {
-		WhatsAppSendText(w, r)
+		WhatsAppSendMessage(w, r)
 
 	} else {
-		WhatsAppSendImage(w, r)
+		WhatsAppSendMedia(w, r)
 	}
 }
 
-func WhatsAppSendText(w http.ResponseWriter, r *http.Request) {
-	jid, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
-	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
-		return
-	}
+func WhatsAppSendMessage(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
 
 	var reqBody reqWhatsAppSendMessage
 	_ = json.NewDecoder(r.Body).Decode(&reqBody)
 
-	if len(reqBody.MSISDN) == 0 || len(reqBody.Message) == 0 {
-		svc.ResponseBadRequest(w, "")
+	if len(reqBody.MSISDN) == 0 {
+		responseError(w, hlp.ErrInvalidRecipient)
 		return
 	}
 
-	err = hlp.WAMessageText(jid, reqBody.MSISDN, reqBody.Message, reqBody.Delay)
+	sendAt, err := reqBody.sendAt()
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+		svc.ResponseBadRequest(w, err.Error())
 		return
 	}
 
-	svc.ResponseSuccess(w, "")
-}
+	msg := hlp.QueueMessage{
+		Type:   strings.ToLower(reqBody.Type),
+		To:     reqBody.MSISDN,
+		SendAt: sendAt,
+	}
+
+	switch msg.Type {
+	case "", "text":
+		if len(reqBody.Message) == 0 {
+			svc.ResponseBadRequest(w, "")
+			return
+		}
 
-func WhatsAppSendImage(w http.ResponseWriter, r *http.Request) {
-	jid, err := svc.GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+		msg.Type = "text"
+		msg.Text = reqBody.Message
+	case "location":
+		if reqBody.Lat == nil || reqBody.Lng == nil || *reqBody.Lat < -90 || *reqBody.Lat > 90 || *reqBody.Lng < -180 || *reqBody.Lng > 180 {
+			svc.ResponseBadRequest(w, "invalid location coordinates")
+			return
+		}
+
+		msg.Latitude = *reqBody.Lat
+		msg.Longitude = *reqBody.Lng
+		msg.Name = reqBody.Name
+		msg.Address = reqBody.Address
+	case "contact":
+		msg.VCard = reqBody.VCard
+		if len(msg.VCard) == 0 {
+			if len(reqBody.Name) == 0 || len(reqBody.Phone) == 0 {
+				svc.ResponseBadRequest(w, "contact requires a vcard or a name and phone")
+				return
+			}
+
+			msg.VCard = hlp.WAVCard(reqBody.Name, reqBody.Phone, reqBody.Organization, reqBody.Email)
+		}
+
+		msg.Name = reqBody.Name
+		if len(msg.Name) == 0 {
+			msg.Name = hlp.WAVCardName(msg.VCard)
+		}
+	default:
+		svc.ResponseBadRequest(w, "unsupported message type "+reqBody.Type)
+		return
+	}
+
+	err = reqBody.quote(jid, &msg)
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+		responseError(w, err)
 		return
 	}
 
-	err = r.ParseMultipartForm(svc.Config.GetInt64("SERVER_UPLOAD_LIMIT"))
+	msg, err = hlp.WAQueueMessage(jid, msg)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	responseQueued(w, msg)
+}
+
+func WhatsAppSendMedia(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	err := r.ParseMultipartForm(svc.Config.GetInt64("SERVER_UPLOAD_LIMIT"))
 	if err != nil {
 		svc.ResponseInternalError(w, err.Error())
 		return
@@ -210,6 +357,10 @@ func WhatsAppSendImage(w http.ResponseWriter, r *http.Request) {
 
 	reqBody.MSISDN = r.FormValue("msisdn")
 	reqBody.Message = r.FormValue("message")
+	reqBody.SendAt = r.FormValue("send_at")
+	reqBody.QuotedMessageID = r.FormValue("quoted_message_id")
+	reqBody.QuotedText = r.FormValue("quoted_text")
+	reqBody.QuotedParticipant = r.FormValue("quoted_participant")
 	reqDelay := r.FormValue("delay")
 
 	if len(reqDelay) == 0 {
@@ -222,25 +373,150 @@ func WhatsAppSendImage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	mpFileStream, mpFileHeader, err := r.FormFile("image")
+	if len(reqBody.MSISDN) == 0 {
+		responseError(w, hlp.ErrInvalidRecipient)
+		return
+	}
+
+	var msgType string
+	for _, mediaType := range hlp.WAMediaTypes() {
+		if _, ok := r.MultipartForm.File[mediaType]; ok {
+			msgType = mediaType
+			break
+		}
+	}
+
+	if len(msgType) == 0 {
+		svc.ResponseBadRequest(w, "missing media file, expected one of "+strings.Join(hlp.WAMediaTypes(), ", "))
+		return
+	}
+
+	mpFileStream, mpFileHeader, err := r.FormFile(msgType)
 	if err != nil {
 		svc.ResponseBadRequest(w, err.Error())
 		return
 	}
 	defer mpFileStream.Close()
 
-	mpFileType := mpFileHeader.Header.Get("Content-Type")
+	mpFileType, err := hlp.WAMediaSniff(msgType, mpFileStream, mpFileHeader.Filename)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
 
-	if len(reqBody.MSISDN) == 0 || len(reqBody.Message) == 0 {
-		svc.ResponseBadRequest(w, "")
+	sendAt, err := reqBody.sendAt()
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	msg := hlp.QueueMessage{
+		Type:      msgType,
+		To:        reqBody.MSISDN,
+		MediaType: mpFileType,
+		SendAt:    sendAt,
+	}
+
+	switch msgType {
+	case "image", "video":
+		msg.Text = reqBody.Message
+	case "document":
+		msg.FileName = r.FormValue("filename")
+		if len(msg.FileName) == 0 {
+			msg.FileName = filepath.Base(mpFileHeader.Filename)
+		}
+	case "audio":
+		msg.PTT, _ = strconv.ParseBool(r.FormValue("ptt"))
+	}
+
+	err = reqBody.quote(jid, &msg)
+	if err != nil {
+		responseError(w, err)
 		return
 	}
 
-	err = hlp.WAMessageImage(jid, reqBody.MSISDN, mpFileStream, mpFileType, reqBody.Message, reqBody.Delay)
+	var thumbnail io.Reader
+	if msgType == "video" {
+		thumbFileStream, _, err := r.FormFile("thumbnail")
+		if err == nil {
+			defer thumbFileStream.Close()
+			thumbnail = thumbFileStream
+		}
+	}
+
+	msg, err = hlp.WAQueueMedia(jid, msg, mpFileStream, thumbnail)
 	if err != nil {
-		svc.ResponseInternalError(w, err.Error())
+		responseError(w, err)
 		return
 	}
 
-	svc.ResponseSuccess(w, "")
+	responseQueued(w, msg)
+}
+
+func WhatsAppGetMessage(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	msg, err := hlp.WAQueueGet(jid, chi.URLParam(r, "messageID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppMessage
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = msg
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppGetScheduled(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	msgs, err := hlp.WAQueueScheduled(jid)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppMessages
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = msgs
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppCancelScheduled(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	msg, err := hlp.WAQueueCancel(jid, chi.URLParam(r, "messageID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppMessage
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = msg
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func responseQueued(w http.ResponseWriter, msg hlp.QueueMessage) {
+	var response resWhatsAppMessage
+
+	response.Status = true
+	response.Code = http.StatusAccepted
+	response.Message = "Accepted"
+	response.Data = msg
+
+	svc.ResponseWrite(w, response.Code, response)
 }
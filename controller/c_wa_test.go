@@ -0,0 +1,337 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestWhatsAppSendMessage(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "sender")
+	client := testLogin(t, srv, token)
+
+	var msg hlp.QueueMessage
+
+	code := testRequest(t, srv, http.MethodPost, "/messagetext", token, `{"msisdn":"6281234567890","message":"hello"}`, &msg)
+	if code != http.StatusAccepted {
+		t.Fatalf("send answered %d", code)
+	}
+
+	testEventually(t, func() bool {
+		testRequest(t, srv, http.MethodGet, "/messages/"+msg.ID, token, "", &msg)
+		return msg.Status == hlp.QueueSent
+	})
+
+	if msg.Receipt != hlp.ReceiptServerAck {
+		t.Errorf("receipt is %q, want %q", msg.Receipt, hlp.ReceiptServerAck)
+	}
+
+	sent := client.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("client sent %d messages, want 1", len(sent))
+	}
+
+	text, ok := sent[0].(whatsapp.TextMessage)
+	if !ok || text.Text != "hello" || text.Info.RemoteJid != "6281234567890@s.whatsapp.net" {
+		t.Errorf("client sent %+v", sent[0])
+	}
+
+	presences := client.Presences()
+	if len(presences) != 1 || presences[0] != "6281234567890@s.whatsapp.net composing" {
+		t.Errorf("client sent presences %v", presences)
+	}
+}
+
+func TestWhatsAppGetMessageOfOtherJID(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "queued")
+
+	var msg hlp.QueueMessage
+
+	code := testRequest(t, srv, http.MethodPost, "/messagetext", token, `{"msisdn":"6281234567890","message":"hello"}`, &msg)
+	if code != http.StatusAccepted || msg.Status != hlp.QueueQueued {
+		t.Fatalf("send without a connection answered %d with %+v", code, msg)
+	}
+
+	code = testRequest(t, srv, http.MethodGet, "/messages/"+msg.ID, testUser(t, srv, "other"), "", nil)
+	if code != http.StatusNotFound {
+		t.Errorf("message of another JID answered %d", code)
+	}
+}
+
+func TestGetAuthWrongPassword(t *testing.T) {
+	srv := testServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/auth", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.SetBasicAuth("sender", "wrong-password")
+
+	code, _ := testDo(t, req)
+	if code != http.StatusBadRequest {
+		t.Errorf("wrong password answered %d", code)
+	}
+}
+
+func TestWhatsAppGetSession(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "session")
+
+	code := testRequest(t, srv, http.MethodGet, "/sessions/session", token, "", nil)
+	if code != http.StatusNotFound {
+		t.Fatalf("session before login answered %d", code)
+	}
+
+	testLogin(t, srv, token)
+
+	var session hlp.SessionInfo
+
+	testEventually(t, func() bool {
+		testRequest(t, srv, http.MethodGet, "/sessions/session", token, "", &session)
+		return session.State == hlp.StateConnected
+	})
+
+	code = testRequest(t, srv, http.MethodGet, "/sessions/sender", token, "", nil)
+	if code != http.StatusForbidden {
+		t.Errorf("session of another JID answered %d", code)
+	}
+}
+
+func TestWhatsAppCancelScheduled(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "scheduler")
+	client := testLogin(t, srv, token)
+
+	var msg hlp.QueueMessage
+
+	body := `{"msisdn":"6281234567890","message":"tomorrow","send_at":"` + time.Now().Add(24*time.Hour).Format(time.RFC3339) + `"}`
+
+	code := testRequest(t, srv, http.MethodPost, "/messagetext", token, body, &msg)
+	if code != http.StatusAccepted || msg.Status != hlp.QueueScheduled {
+		t.Fatalf("send answered %d with %+v", code, msg)
+	}
+
+	var scheduled []hlp.QueueMessage
+
+	code = testRequest(t, srv, http.MethodGet, "/scheduled", token, "", &scheduled)
+	if code != http.StatusOK || len(scheduled) != 1 || scheduled[0].ID != msg.ID {
+		t.Fatalf("scheduled answered %d with %+v", code, scheduled)
+	}
+
+	code = testRequest(t, srv, http.MethodDelete, "/scheduled/"+msg.ID, token, "", &msg)
+	if code != http.StatusOK || msg.Status != hlp.QueueCancelled {
+		t.Fatalf("cancel answered %d with %+v", code, msg)
+	}
+
+	code = testRequest(t, srv, http.MethodDelete, "/scheduled/"+msg.ID, token, "", nil)
+	if code != http.StatusConflict {
+		t.Errorf("cancelling twice answered %d", code)
+	}
+
+	if sent := client.Sent(); len(sent) != 0 {
+		t.Errorf("client sent %+v", sent)
+	}
+}
+
+func TestWhatsAppSendDocument(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "documents")
+	client := testLogin(t, srv, token)
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+	form.WriteField("msisdn", "6281234567890")
+
+	file, err := form.CreateFormFile("document", "report.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte("%PDF-1.4 quarterly report"))
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/messages", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	code, data := testDo(t, req)
+	if code != http.StatusAccepted {
+		t.Fatalf("send answered %d", code)
+	}
+
+	var msg hlp.QueueMessage
+
+	err = json.Unmarshal(data, &msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Type != "document" || msg.MediaType != "application/pdf" {
+		t.Fatalf("queued %+v", msg)
+	}
+
+	testEventually(t, func() bool {
+		testRequest(t, srv, http.MethodGet, "/messages/"+msg.ID, token, "", &msg)
+		return msg.Status == hlp.QueueSent
+	})
+
+	document, ok := client.Sent()[0].(whatsapp.DocumentMessage)
+	if !ok || document.FileName != "report.pdf" || document.Type != "application/pdf" {
+		t.Errorf("client sent %+v", client.Sent()[0])
+	}
+}
+
+func TestWhatsAppSendLocationAndContact(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "pins")
+	client := testLogin(t, srv, token)
+
+	messages := []string{
+		`{"type":"location","msisdn":"6281234567890","lat":-6.2,"lng":106.8,"name":"Monas"}`,
+		`{"type":"contact","msisdn":"6281234567890","name":"Budi","phone":"+62 812-0000-0001"}`,
+	}
+
+	for _, body := range messages {
+		var msg hlp.QueueMessage
+
+		code := testRequest(t, srv, http.MethodPost, "/messages", token, body, &msg)
+		if code != http.StatusAccepted {
+			t.Fatalf("send of %s answered %d", body, code)
+		}
+
+		testEventually(t, func() bool {
+			testRequest(t, srv, http.MethodGet, "/messages/"+msg.ID, token, "", &msg)
+			return msg.Status == hlp.QueueSent
+		})
+	}
+
+	sent := client.Sent()
+
+	location, ok := sent[0].(whatsapp.LocationMessage)
+	if !ok || location.DegreesLatitude != -6.2 || location.DegreesLongitude != 106.8 || location.Name != "Monas" {
+		t.Errorf("client sent %+v", sent[0])
+	}
+
+	contact, ok := sent[1].(whatsapp.ContactMessage)
+	if !ok || contact.DisplayName != "Budi" || !strings.Contains(contact.Vcard, "waid=6281200000001") {
+		t.Errorf("client sent %+v", sent[1])
+	}
+
+	for _, body := range []string{
+		`{"type":"location","msisdn":"6281234567890","lat":91,"lng":0}`,
+		`{"type":"contact","msisdn":"6281234567890","name":"Budi"}`,
+		`{"type":"poll","msisdn":"6281234567890"}`,
+	} {
+		code := testRequest(t, srv, http.MethodPost, "/messages", token, body, nil)
+		if code != http.StatusBadRequest {
+			t.Errorf("send of %s answered %d", body, code)
+		}
+	}
+}
+
+func TestWhatsAppSendReply(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "replies")
+	client := testLogin(t, srv, token)
+
+	client.Emit(whatsapp.TextMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        "QUOTED0001",
+			RemoteJid: "6281234567890@s.whatsapp.net",
+			Timestamp: uint64(time.Now().Unix()),
+		},
+		Text: "are you there?",
+	})
+
+	var msg hlp.QueueMessage
+
+	code := testRequest(t, srv, http.MethodPost, "/messages", token, `{"msisdn":"6281234567890","message":"yes","quoted_message_id":"QUOTED0001"}`, &msg)
+	if code != http.StatusAccepted {
+		t.Fatalf("reply answered %d", code)
+	}
+
+	testEventually(t, func() bool {
+		testRequest(t, srv, http.MethodGet, "/messages/"+msg.ID, token, "", &msg)
+		return msg.Status == hlp.QueueSent
+	})
+
+	reply, ok := client.Sent()[0].(whatsapp.TextMessage)
+	if !ok || reply.ContextInfo.QuotedMessageID != "QUOTED0001" || reply.ContextInfo.Participant != "6281234567890@s.whatsapp.net" {
+		t.Fatalf("client sent %+v", client.Sent()[0])
+	}
+
+	code = testRequest(t, srv, http.MethodPost, "/messages", token, `{"msisdn":"6289876543210","message":"wrong chat","quoted_message_id":"QUOTED0001"}`, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("reply to another chat answered %d", code)
+	}
+}
+
+func TestWhatsAppSendMessageInvalidRecipient(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "invalid")
+
+	code := testRequest(t, srv, http.MethodPost, "/messages", token, `{"msisdn":"not a number","message":"hello"}`, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("send answered %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestWhatsAppIncomingMessage(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "receiver")
+	client := testLogin(t, srv, token)
+
+	client.Emit(whatsapp.TextMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        "INCOMING0001",
+			RemoteJid: "6281234567890@s.whatsapp.net",
+			Timestamp: uint64(time.Now().Unix()),
+		},
+		Text: "incoming greetings",
+	})
+
+	var page hlp.HistoryPage
+
+	code := testRequest(t, srv, http.MethodGet, "/chats/6281234567890/messages", token, "", &page)
+	if code != http.StatusOK {
+		t.Fatalf("history answered %d", code)
+	}
+
+	if len(page.Messages) != 1 || page.Messages[0].ID != "INCOMING0001" || page.Messages[0].Direction != hlp.HistoryInbound {
+		t.Fatalf("history is %+v", page.Messages)
+	}
+
+	code = testRequest(t, srv, http.MethodGet, "/search?q=Greetings", token, "", &page)
+	if code != http.StatusOK {
+		t.Fatalf("search answered %d", code)
+	}
+
+	if len(page.Messages) != 1 || page.Messages[0].ID != "INCOMING0001" {
+		t.Errorf("search found %+v", page.Messages)
+	}
+}
+
+func TestWhatsAppLoginWebhookWithoutSecret(t *testing.T) {
+	srv := testServer(t)
+	token := testUser(t, srv, "unsigned")
+
+	code := testRequest(t, srv, http.MethodPost, "/login", token, `{"webhook":"http://127.0.0.1/hook"}`, nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("login with an unsigned webhook answered %d, want %d", code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/go-chi/chi"
+)
+
+type reqWhatsAppWebhook struct {
+	URL      string   `json:"url"`
+	Secret   string   `json:"secret"`
+	Events   []string `json:"events"`
+	Envelope bool     `json:"envelope"`
+}
+
+type resWhatsAppWebhook struct {
+	Status  bool                  `json:"status"`
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Data    hlp.WebhookSubscriber `json:"data"`
+}
+
+type resWhatsAppWebhooks struct {
+	Status  bool                    `json:"status"`
+	Code    int                     `json:"code"`
+	Message string                  `json:"message"`
+	Data    []hlp.WebhookSubscriber `json:"data"`
+}
+
+type resWhatsAppWebhookDelivery struct {
+	Status  bool                `json:"status"`
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    hlp.WebhookDelivery `json:"data"`
+}
+
+type resWhatsAppWebhookDeliveries struct {
+	Status  bool                  `json:"status"`
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Data    []hlp.WebhookDelivery `json:"data"`
+}
+
+func WhatsAppGetWebhooks(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	subscribers, err := hlp.WAWebhookList(jid)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	// Secrets Are Write Only
+	for i := range subscribers {
+		subscribers[i].Secret = ""
+	}
+
+	var response resWhatsAppWebhooks
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = subscribers
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppPutWebhook(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	var reqBody reqWhatsAppWebhook
+
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		svc.ResponseBadRequest(w, err.Error())
+		return
+	}
+
+	subscriber, err := hlp.WAWebhookPut(jid, hlp.WebhookSubscriber{
+		URL:      reqBody.URL,
+		Secret:   reqBody.Secret,
+		Events:   reqBody.Events,
+		Envelope: reqBody.Envelope,
+	})
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	// Secrets Are Write Only, Except a Generated One Which is Returned Once
+	if len(reqBody.Secret) > 0 {
+		subscriber.Secret = ""
+	}
+
+	var response resWhatsAppWebhook
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = subscriber
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	err := hlp.WAWebhookDelete(jid, chi.URLParam(r, "webhookID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	svc.ResponseSuccess(w, "")
+}
+
+func WhatsAppGetWebhookFailed(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	deliveries, err := hlp.WAWebhookFailed(jid)
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppWebhookDeliveries
+
+	response.Status = true
+	response.Code = http.StatusOK
+	response.Message = "Success"
+	response.Data = deliveries
+
+	svc.ResponseWrite(w, response.Code, response)
+}
+
+func WhatsAppReplayWebhookFailed(w http.ResponseWriter, r *http.Request) {
+	jid := requestAccount(r)
+
+	delivery, err := hlp.WAWebhookReplay(jid, chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		responseError(w, err)
+		return
+	}
+
+	var response resWhatsAppWebhookDelivery
+
+	response.Status = true
+	response.Code = http.StatusAccepted
+	response.Message = "Accepted"
+	response.Data = delivery
+
+	svc.ResponseWrite(w, response.Code, response)
+}
@@ -0,0 +1,502 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store Bucket Keeping Accounts by ID
+const bucketAccounts = "accounts"
+
+// accountBuckets Lists The Store Buckets Keeping a Nested Bucket Per Account
+var accountBuckets = []string{
+	bucketHistory,
+	bucketSearch,
+	bucketSearchCounts,
+	bucketMessages,
+	bucketOutbox,
+	bucketDeadLetter,
+	bucketContacts,
+	bucketRoster,
+	bucketEvents,
+	bucketWebhookPending,
+	bucketWebhookFailed,
+}
+
+// accountIDPattern Restricts Account IDs to Names Safe For Session Files
+var accountIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// Account Struct Describing a WhatsApp Account and The API Users
+// Granted Access to it
+type Account struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Users     []string  `json:"users"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WAAccountAllowed Function Reports Whether a User May Act on an Account,
+// Creating The Account Named After The User For it on First Use
+func WAAccountAllowed(user string, id string) (bool, error) {
+	account, found, err := accountGet(id)
+	if err != nil {
+		return false, err
+	}
+
+	if !found && id == user {
+		account, err = accountDefault(user)
+		if err != nil {
+			return false, err
+		}
+
+		found = true
+	}
+
+	if !found {
+		return accountAdmin(user), nil
+	}
+
+	return account.granted(user) || accountAdmin(user), nil
+}
+
+// WAAccountList Function Returns The Accounts a User Was Granted Access to,
+// Every Account For Admins
+func WAAccountList(user string) ([]Account, error) {
+	accounts := make([]Account, 0)
+	admin := accountAdmin(user)
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketAccounts)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var account Account
+
+			err := json.Unmarshal(v, &account)
+			if err != nil {
+				return err
+			}
+
+			if admin || account.granted(user) {
+				accounts = append(accounts, account)
+			}
+
+			return nil
+		})
+	})
+
+	return accounts, err
+}
+
+// WAAccountGet Function Returns an Account a User Was Granted Access to
+func WAAccountGet(user string, id string) (Account, error) {
+	account, found, err := accountGet(id)
+	if err != nil {
+		return Account{}, err
+	}
+
+	// Accounts a User Has no Access to Are Not Revealed to Exist
+	if !found || !(account.granted(user) || accountAdmin(user)) {
+		return Account{}, fmt.Errorf("account %w", ErrNotFound)
+	}
+
+	return account, nil
+}
+
+// WAAccountCreate Function Creates an Account Granting Access to The User
+// Creating it Along With The Users Listed
+func WAAccountCreate(user string, account Account) (Account, error) {
+	if !accountIDPattern.MatchString(account.ID) {
+		return Account{}, &waError{kind: ErrInvalidArgument, err: errors.New("account id must be up to 64 letters, digits, dots, dashes or underscores")}
+	}
+
+	// The Account Named After a User is Kept For That User, and a Session
+	// Stored Under The ID Belongs to an Account Already
+	if account.ID != user {
+		if _, err := svc.UserGet(account.ID); err == nil {
+			return Account{}, &waError{kind: ErrConflict, err: errors.New("account " + account.ID + " is reserved for the user of that name")}
+		}
+
+		if _, err := os.Stat(WASessionFile(account.ID)); err == nil {
+			return Account{}, &waError{kind: ErrConflict, err: errors.New("account " + account.ID + " already exists")}
+		}
+	}
+
+	for _, grantee := range account.Users {
+		if _, err := svc.UserGet(grantee); err != nil {
+			return Account{}, err
+		}
+	}
+
+	now := time.Now()
+
+	account.Users = accountUsers(append(account.Users, user))
+	account.CreatedAt = now
+	account.UpdatedAt = now
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket, err := storeBucket(tx, bucketAccounts)
+		if err != nil {
+			return err
+		}
+
+		if bucket.Get([]byte(account.ID)) != nil {
+			return &waError{kind: ErrConflict, err: errors.New("account " + account.ID + " already exists")}
+		}
+
+		return accountPut(bucket, account)
+	})
+	if err != nil {
+		return Account{}, err
+	}
+
+	return account, nil
+}
+
+// WAAccountGrant Function Grants a User Access to an Account
+func WAAccountGrant(user string, id string, grantee string) (Account, error) {
+	_, err := svc.UserGet(grantee)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return accountUpdate(user, id, func(account *Account) error {
+		account.Users = accountUsers(append(account.Users, grantee))
+
+		return nil
+	})
+}
+
+// WAAccountRevoke Function Revokes The Access of a User to an Account,
+// Keeping at Least One User Able to Manage it
+func WAAccountRevoke(user string, id string, grantee string) (Account, error) {
+	return accountUpdate(user, id, func(account *Account) error {
+		if !account.granted(grantee) {
+			return fmt.Errorf("user %w", ErrNotFound)
+		}
+
+		if len(account.Users) == 1 {
+			return &waError{kind: ErrConflict, err: errors.New("the last user of an account can not be revoked")}
+		}
+
+		users := make([]string, 0, len(account.Users)-1)
+		for _, u := range account.Users {
+			if u != grantee {
+				users = append(users, u)
+			}
+		}
+
+		account.Users = users
+
+		return nil
+	})
+}
+
+// WAAccountDelete Function Logs an Account Out and Deletes it
+func WAAccountDelete(user string, id string) error {
+	_, err := WAAccountGet(user, id)
+	if err != nil {
+		return err
+	}
+
+	return accountRemove(id)
+}
+
+// WAUserDelete Function Deletes a User Revoking its Access to Every Account,
+// Accounts no User is Left With, Like The One Named After The User When it
+// Was Not Shared, Are Deleted Along With it
+func WAUserDelete(username string) error {
+	err := svc.UserDelete(username)
+	if err != nil {
+		return err
+	}
+
+	var retired []string
+
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketAccounts)
+		if bucket == nil {
+			return nil
+		}
+
+		var accounts []Account
+
+		err := bucket.ForEach(func(k, v []byte) error {
+			var account Account
+
+			err := json.Unmarshal(v, &account)
+			if err != nil {
+				return err
+			}
+
+			if account.granted(username) {
+				accounts = append(accounts, account)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			users := make([]string, 0, len(account.Users))
+			for _, user := range account.Users {
+				if user != username {
+					users = append(users, user)
+				}
+			}
+
+			if len(users) == 0 {
+				retired = append(retired, account.ID)
+			}
+
+			account.Users = users
+			account.UpdatedAt = time.Now()
+
+			err = accountPut(bucket, account)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range retired {
+		err = accountRemove(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accountRemove Function Logs an Account Out and Purges it
+func accountRemove(id string) error {
+	loginForget(id)
+
+	file := WASessionFile(id)
+
+	err := WASessionLogout(id, file)
+	if errors.Is(err, ErrNotConnected) {
+		// Disconnected Sessions Are Forgotten Without Telling WhatsApp
+		waUnsupervise(id)
+
+		err = os.Remove(file)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return accountPurge(id)
+}
+
+// accountPurge Function Deletes an Account Together With Everything Stored
+// For it, so an Account Created Later With The Same ID Starts Empty
+func accountPurge(id string) error {
+	queueStop(id)
+
+	var messages []string
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		if bucket := storeBucketRead(tx, bucketMessages, id); bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				messages = append(messages, string(k))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, name := range accountBuckets {
+			bucket := storeBucketRead(tx, name)
+			if bucket == nil || bucket.Bucket([]byte(id)) == nil {
+				continue
+			}
+
+			err := bucket.DeleteBucket([]byte(id))
+			if err != nil {
+				return err
+			}
+		}
+
+		bucket := storeBucketRead(tx, bucketAccounts)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.Delete([]byte(id))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		queueMediaRemove(message)
+	}
+
+	rosterClear(id)
+	quoteForget(id)
+	restoreForget(id)
+
+	return webhookForget(id)
+}
+
+// accountAdmin Function Reports Whether a User is an Admin, Who Has Access
+// to Every Account
+func accountAdmin(user string) bool {
+	u, err := svc.UserGet(user)
+
+	return err == nil && u.IsAdmin()
+}
+
+// granted Method Reports Whether a User Was Granted Access to The Account
+func (account Account) granted(user string) bool {
+	for _, u := range account.Users {
+		if u == user {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accountGet Function Reads an Account, Reporting Whether it Exists
+func accountGet(id string) (Account, bool, error) {
+	var account Account
+	var found bool
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketAccounts)
+		if bucket == nil {
+			return nil
+		}
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		found = true
+
+		return json.Unmarshal(data, &account)
+	})
+
+	return account, found, err
+}
+
+// accountDefault Function Returns The Account Named After a User, Creating
+// it With The User as its Only User When Missing
+func accountDefault(user string) (Account, error) {
+	var account Account
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket, err := storeBucket(tx, bucketAccounts)
+		if err != nil {
+			return err
+		}
+
+		if data := bucket.Get([]byte(user)); data != nil {
+			return json.Unmarshal(data, &account)
+		}
+
+		now := time.Now()
+
+		account = Account{
+			ID:        user,
+			Users:     []string{user},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		return accountPut(bucket, account)
+	})
+
+	return account, err
+}
+
+// accountUpdate Function Changes an Account a User Was Granted Access to
+func accountUpdate(user string, id string, update func(*Account) error) (Account, error) {
+	var account Account
+
+	// Users Are Read Before The Update as Read and Write Transactions Must
+	// Not be Nested
+	admin := accountAdmin(user)
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketAccounts)
+		if bucket == nil {
+			return fmt.Errorf("account %w", ErrNotFound)
+		}
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("account %w", ErrNotFound)
+		}
+
+		err := json.Unmarshal(data, &account)
+		if err != nil {
+			return err
+		}
+
+		if !admin && !account.granted(user) {
+			return fmt.Errorf("account %w", ErrNotFound)
+		}
+
+		err = update(&account)
+		if err != nil {
+			return err
+		}
+
+		account.UpdatedAt = time.Now()
+
+		return accountPut(bucket, account)
+	})
+
+	return account, err
+}
+
+// accountPut Function Writes an Account
+func accountPut(bucket *bolt.Bucket, account Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(account.ID), data)
+}
+
+// accountUsers Function Sorts a List of Users Dropping Duplicates and Blanks
+func accountUsers(users []string) []string {
+	sort.Strings(users)
+
+	unique := make([]string, 0, len(users))
+	for _, user := range users {
+		if len(user) > 0 && (len(unique) == 0 || unique[len(unique)-1] != user) {
+			unique = append(unique, user)
+		}
+	}
+
+	return unique
+}
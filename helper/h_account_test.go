@@ -0,0 +1,109 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestAccountDeletePurges(t *testing.T) {
+	user := "purge-owner"
+	id := "purge-" + webhookID()
+	chat := "6281234567890@s.whatsapp.net"
+
+	_, err := WAAccountCreate(user, Account{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	historyPut(id, HistoryMessage{ID: "PURGE0001", Chat: chat, Direction: HistoryInbound, Type: "text", Text: "purged invoice", Timestamp: time.Now()})
+
+	_, err = WAWebhookPut(id, WebhookSubscriber{URL: "http://127.0.0.1/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Queued Message With its Media File
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		return queuePut(tx, QueueMessage{ID: "PURGE0002", JID: id, To: chat, Type: "image"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(queueMediaFile("PURGE0002")), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(queueMediaFile("PURGE0002"), []byte("image"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publishEvent(id, EventState, SessionInfo{State: StateDisconnected})
+
+	err = WAAccountDelete(user, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(queueMediaFile("PURGE0002")); !os.IsNotExist(err) {
+		t.Fatalf("queued media survived the account: %v", err)
+	}
+
+	// An Account Created Again Under The Same ID Starts Empty
+	_, err = WAAccountCreate(user, Account{ID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := WAHistory(id, chat, HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history.Messages) != 0 {
+		t.Fatalf("history survived the account: %+v", history.Messages)
+	}
+
+	if ids := testSearch(t, id, "invoice"); len(ids) != 0 {
+		t.Fatalf("search index survived the account: %v", ids)
+	}
+
+	subscribers, err := WAWebhookList(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(subscribers) != 0 {
+		t.Fatalf("webhooks survived the account: %+v", subscribers)
+	}
+
+	err = svc.Store.View(func(tx *bolt.Tx) error {
+		_, found, err := queueGet(tx, id, "PURGE0002")
+		if err == nil && found {
+			t.Error("queued message survived the account")
+		}
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := WAEventsSince(id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("events survived the account: %+v", events)
+	}
+}
@@ -0,0 +1,131 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+// Client Interface for a WhatsApp Web Connection
+type Client interface {
+	Login(qr chan<- string) (whatsapp.Session, error)
+	RestoreWithSession(session whatsapp.Session) (whatsapp.Session, error)
+	Send(message interface{}) (string, error)
+	Presence(jid string, presence whatsapp.Presence) (<-chan string, error)
+	Exist(jid string) (<-chan string, error)
+	CreateGroup(subject string, participants []string) (<-chan string, error)
+	UpdateGroupSubject(subject string, jid string) (<-chan string, error)
+	AddMember(jid string, participants []string) (<-chan string, error)
+	RemoveMember(jid string, participants []string) (<-chan string, error)
+	SetAdmin(jid string, participants []string) (<-chan string, error)
+	RemoveAdmin(jid string, participants []string) (<-chan string, error)
+	GetGroupMetaData(jid string) (<-chan string, error)
+	GroupInviteLink(jid string) (string, error)
+	LeaveGroup(jid string) (<-chan string, error)
+	Logout() error
+	Disconnect() error
+	AddHandler(handler whatsapp.Handler)
+	RemoveHandlers()
+}
+
+// NewClient Function Used by WAInit to Create a Client,
+// Replace it to Run Against a Different Implementation
+var NewClient = func(timeout int) (Client, error) {
+	return newRhymenClient(timeout)
+}
+
+// rhymenClient Struct Adapting *whatsapp.Conn to Client
+type rhymenClient struct {
+	conn *whatsapp.Conn
+}
+
+func newRhymenClient(timeout int) (*rhymenClient, error) {
+	conn, err := whatsapp.NewConn(time.Duration(timeout) * time.Second)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetClientName("Go WhatsApp REST", "Go WhatsApp")
+
+	info, err := WASyncVersion(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("[+] %v\n", info)
+
+	return &rhymenClient{conn: conn}, nil
+}
+
+func (c *rhymenClient) Login(qr chan<- string) (whatsapp.Session, error) {
+	return c.conn.Login(qr)
+}
+
+func (c *rhymenClient) RestoreWithSession(session whatsapp.Session) (whatsapp.Session, error) {
+	return c.conn.RestoreWithSession(session)
+}
+
+func (c *rhymenClient) Send(message interface{}) (string, error) {
+	return c.conn.Send(message)
+}
+
+func (c *rhymenClient) Presence(jid string, presence whatsapp.Presence) (<-chan string, error) {
+	return c.conn.Presence(jid, presence)
+}
+
+func (c *rhymenClient) Exist(jid string) (<-chan string, error) {
+	return c.conn.Exist(jid)
+}
+
+func (c *rhymenClient) CreateGroup(subject string, participants []string) (<-chan string, error) {
+	return c.conn.CreateGroup(subject, participants)
+}
+
+func (c *rhymenClient) UpdateGroupSubject(subject string, jid string) (<-chan string, error) {
+	return c.conn.UpdateGroupSubject(subject, jid)
+}
+
+func (c *rhymenClient) AddMember(jid string, participants []string) (<-chan string, error) {
+	return c.conn.AddMember(jid, participants)
+}
+
+func (c *rhymenClient) RemoveMember(jid string, participants []string) (<-chan string, error) {
+	return c.conn.RemoveMember(jid, participants)
+}
+
+func (c *rhymenClient) SetAdmin(jid string, participants []string) (<-chan string, error) {
+	return c.conn.SetAdmin(jid, participants)
+}
+
+func (c *rhymenClient) RemoveAdmin(jid string, participants []string) (<-chan string, error) {
+	return c.conn.RemoveAdmin(jid, participants)
+}
+
+func (c *rhymenClient) GetGroupMetaData(jid string) (<-chan string, error) {
+	return c.conn.GetGroupMetaData(jid)
+}
+
+func (c *rhymenClient) GroupInviteLink(jid string) (string, error) {
+	return c.conn.GroupInviteLink(jid)
+}
+
+func (c *rhymenClient) LeaveGroup(jid string) (<-chan string, error) {
+	return c.conn.LeaveGroup(jid)
+}
+
+func (c *rhymenClient) Logout() error {
+	return c.conn.Logout()
+}
+
+func (c *rhymenClient) Disconnect() error {
+	_, err := c.conn.Disconnect()
+	return err
+}
+
+func (c *rhymenClient) AddHandler(handler whatsapp.Handler) {
+	c.conn.AddHandler(handler)
+}
+
+func (c *rhymenClient) RemoveHandlers() {
+	c.conn.RemoveHandlers()
+}
@@ -0,0 +1,208 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wajid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ContactCheckTTL is How Long a Contact Check Result is Reused
+// Before The Number is Checked With WhatsApp Again
+var ContactCheckTTL = 24 * time.Hour
+
+// ContactCheckTimeout is How Long a Request May Spend Asking WhatsApp,
+// Numbers Not Checked by Then Are Answered as Not Checked
+var ContactCheckTimeout = 30 * time.Second
+
+// ContactCheckMax is The Number of Phone Numbers Checked per Request
+const ContactCheckMax = 256
+
+// Store Bucket Caching Contact Check Results
+const bucketContacts = "contacts"
+
+// ContactCheck Struct Describing Whether a Phone Number is on WhatsApp
+type ContactCheck struct {
+	Number    string    `json:"number"`
+	JID       string    `json:"jid,omitempty"`
+	Exists    bool      `json:"exists"`
+	Cached    bool      `json:"cached"`
+	Error     string    `json:"error,omitempty"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// contactExist Struct Describing The Answer of WhatsApp to a Check
+type contactExist struct {
+	Status int    `json:"status"`
+	JID    string `json:"jid"`
+}
+
+// WAContactCheck Function Reports Which Phone Numbers Are on WhatsApp,
+// Asking The Connection of a JID Only For Numbers Not Checked Recently
+// and For no Longer Than ContactCheckTimeout
+func WAContactCheck(jid string, numbers []string) ([]ContactCheck, error) {
+	if len(numbers) > ContactCheckMax {
+		return nil, &waError{kind: ErrInvalidArgument, err: errors.New("at most " + strconv.Itoa(ContactCheckMax) + " numbers can be checked at once")}
+	}
+
+	checks := make([]ContactCheck, len(numbers))
+	keys := make([]string, len(numbers))
+	missing := make([]int, 0, len(numbers))
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		contacts := storeBucketRead(tx, bucketContacts, jid)
+
+		for i, number := range numbers {
+			checks[i].Number = number
+
+			remote, err := wajid.Parse(number)
+			if err == nil && remote.Server != wajid.ServerUser {
+				err = errors.New("recipient is not a phone number")
+			}
+
+			if err != nil {
+				checks[i].Error = err.Error()
+				_, checks[i].ErrorCode = WAErrorCode(ErrInvalidRecipient)
+				continue
+			}
+
+			keys[i] = remote.String()
+
+			if contacts != nil {
+				if data := contacts.Get([]byte(keys[i])); data != nil {
+					var cached ContactCheck
+
+					err = json.Unmarshal(data, &cached)
+					if err != nil {
+						return err
+					}
+
+					if time.Since(cached.CheckedAt) < ContactCheckTTL {
+						cached.Number = number
+						cached.Cached = true
+						checks[i] = cached
+						continue
+					}
+				}
+			}
+
+			missing = append(missing, i)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) == 0 {
+		return checks, nil
+	}
+
+	conn := wac.Get(jid)
+	if conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	checked := make([]int, 0, len(missing))
+	deadline := time.Now().Add(ContactCheckTimeout)
+
+	for _, i := range missing {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			checks[i].Error = "number was not checked in time"
+			_, checks[i].ErrorCode = WAErrorCode(ErrSendTimeout)
+			continue
+		}
+
+		if remaining > waQueryTimeout {
+			remaining = waQueryTimeout
+		}
+
+		check, err := contactExistCheck(conn, keys[i], remaining)
+		if errors.Is(err, ErrNotConnected) || errors.Is(err, ErrNotLoggedIn) {
+			return nil, err
+		}
+
+		if err != nil {
+			checks[i].Error = err.Error()
+			_, checks[i].ErrorCode = WAErrorCode(err)
+			continue
+		}
+
+		check.Number = checks[i].Number
+		checks[i] = check
+		checked = append(checked, i)
+	}
+
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		contacts, err := storeBucket(tx, bucketContacts, jid)
+		if err != nil {
+			return err
+		}
+
+		for _, i := range checked {
+			data, err := json.Marshal(checks[i])
+			if err != nil {
+				return err
+			}
+
+			err = contacts.Put([]byte(keys[i]), data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		svc.Log("error", "contact", err.Error())
+	}
+
+	return checks, nil
+}
+
+// contactExistCheck Function Asks WhatsApp Whether a JID Exists,
+// Waiting at Most timeout For The Answer
+func contactExistCheck(conn Client, remoteJid string, timeout time.Duration) (ContactCheck, error) {
+	var check ContactCheck
+
+	ch, err := conn.Exist(remoteJid)
+
+	data, err := waQueryWithin(ch, err, timeout)
+	if err != nil {
+		return check, err
+	}
+
+	var answer contactExist
+
+	err = json.Unmarshal(data, &answer)
+	if err != nil {
+		return check, err
+	}
+
+	switch answer.Status {
+	case 200:
+		check.Exists = true
+		check.JID = remoteJid
+
+		// WhatsApp Answers With The JID The Number is Registered Under
+		if remote, err := wajid.Parse(answer.JID); err == nil {
+			check.JID = remote.String()
+		}
+	case 404:
+		check.Exists = false
+	default:
+		return check, fmt.Errorf("contact check failed with status %d", answer.Status)
+	}
+
+	check.CheckedAt = time.Now()
+
+	return check, nil
+}
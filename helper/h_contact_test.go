@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+)
+
+func TestContactCheckDeadline(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	// Every Number Takes Longer to Check Than Half The Deadline
+	client.ExistFunc = func(jid string) (bool, error) {
+		time.Sleep(60 * time.Millisecond)
+		return true, nil
+	}
+
+	timeout := ContactCheckTimeout
+	ContactCheckTimeout = 100 * time.Millisecond
+	defer func() { ContactCheckTimeout = timeout }()
+
+	jid := "checking"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	numbers := []string{"6281200000001", "6281200000002", "6281200000003", "6281200000004"}
+
+	checks, err := WAContactCheck(jid, numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(checks) != len(numbers) {
+		t.Fatalf("got %d checks, want %d", len(checks), len(numbers))
+	}
+
+	if !checks[0].Exists || len(checks[0].Error) > 0 {
+		t.Fatalf("first number was not checked: %+v", checks[0])
+	}
+
+	last := checks[len(checks)-1]
+	if last.Exists || last.ErrorCode != "send_timeout" {
+		t.Fatalf("last number was checked past the deadline: %+v", last)
+	}
+
+	// Numbers Left Unchecked Are Not Cached and Get Checked Next Time
+	ContactCheckTimeout = time.Second
+
+	checks, err = WAContactCheck(jid, numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, check := range checks {
+		if !check.Exists {
+			t.Fatalf("number was not checked again: %+v", check)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+// WhatsApp Errors, Returned Wrapped so Compare Them Using errors.Is
+var (
+	ErrNotConnected     = errors.New("connection is invalid")
+	ErrNotLoggedIn      = errors.New("not logged in")
+	ErrSendTimeout      = errors.New("sending message timed out")
+	ErrLoginTimeout     = errors.New("qr code scan timed out")
+	ErrInvalidRecipient = errors.New("invalid recipient")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrNotFound         = errors.New("not found")
+	ErrConflict         = errors.New("conflict")
+	ErrForbidden        = errors.New("forbidden")
+	ErrNotSupported     = errors.New("not supported")
+)
+
+// errorCodes Maps Every Error to its HTTP Status and Machine-Readable Code
+var errorCodes = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{ErrNotConnected, http.StatusServiceUnavailable, "not_connected"},
+	{ErrNotLoggedIn, http.StatusConflict, "not_logged_in"},
+	{ErrSendTimeout, http.StatusGatewayTimeout, "send_timeout"},
+	{ErrLoginTimeout, http.StatusGatewayTimeout, "login_timeout"},
+	{ErrInvalidRecipient, http.StatusBadRequest, "invalid_recipient"},
+	{ErrInvalidArgument, http.StatusBadRequest, "invalid_argument"},
+	{ErrNotFound, http.StatusNotFound, "not_found"},
+	{ErrConflict, http.StatusConflict, "conflict"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrNotSupported, http.StatusNotImplemented, "not_supported"},
+}
+
+// waError Struct Wrapping a Library Error With The Error Describing it,
+// Keeping The Original Message
+type waError struct {
+	kind error
+	err  error
+}
+
+func (e *waError) Error() string {
+	return e.err.Error()
+}
+
+func (e *waError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *waError) Unwrap() error {
+	return e.err
+}
+
+// WAErrorCode Function Returns The HTTP Status and Code Describing an Error
+func WAErrorCode(err error) (int, string) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.status, c.code
+		}
+	}
+
+	return http.StatusInternalServerError, "internal_error"
+}
+
+// waRetryable Function Reports Whether Sending Again Might Succeed
+func waRetryable(err error) bool {
+	return !errors.Is(err, ErrInvalidRecipient) && !errors.Is(err, ErrInvalidArgument) && !errors.Is(err, ErrNotSupported)
+}
+
+// waErrorWrap Function Classifies an Error Returned by The WhatsApp Library
+func waErrorWrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case isDisconnectError(err), err == whatsapp.ErrNotConnected, err == whatsapp.ErrInvalidWebsocket, err == whatsapp.ErrConnectionTimeout:
+		return &waError{kind: ErrNotConnected, err: err}
+	case err == whatsapp.ErrInvalidSession, err == whatsapp.ErrInvalidWsState:
+		return &waError{kind: ErrNotLoggedIn, err: err}
+	}
+
+	// The Library Reports Timeouts Only as Formatted Messages
+	message := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(message, "qr code scan timed out"):
+		return &waError{kind: ErrLoginTimeout, err: err}
+	case strings.Contains(message, "timed out"):
+		return &waError{kind: ErrSendTimeout, err: err}
+	}
+
+	return err
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{waErrorWrap(whatsapp.ErrNotConnected), http.StatusServiceUnavailable, "not_connected"},
+		{waErrorWrap(whatsapp.ErrInvalidSession), http.StatusConflict, "not_logged_in"},
+		{waErrorWrap(errors.New("sending message timed out")), http.StatusGatewayTimeout, "send_timeout"},
+		{waErrorWrap(errors.New("qr code scan timed out")), http.StatusGatewayTimeout, "login_timeout"},
+		{fmt.Errorf("message %w", ErrNotFound), http.StatusNotFound, "not_found"},
+		{&waError{kind: ErrConflict, err: errors.New("message is being sent")}, http.StatusConflict, "conflict"},
+		{waErrorWrap(errors.New("something else")), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, test := range tests {
+		status, code := WAErrorCode(test.err)
+		if status != test.status || code != test.code {
+			t.Errorf("%v maps to %d %v, want %d %v", test.err, status, code, test.status, test.code)
+		}
+	}
+
+	err := &waError{kind: ErrConflict, err: errors.New("message is being sent")}
+	if err.Error() != "message is being sent" {
+		t.Errorf("wrapped error reads %q", err.Error())
+	}
+}
@@ -0,0 +1,175 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// EventLogSize is The Number of Recent Events Kept per Account
+// so Streams Can Resume After a Disconnect
+var EventLogSize = 1000
+
+// Store Bucket Keeping The Event Log
+const bucketEvents = "events"
+
+// Event Types
+const (
+	EventState   = "state"
+	EventMessage = "message"
+	EventLogin   = "login"
+	EventStatus  = "status"
+)
+
+// Event Struct Describing Something That Happened to an Account
+type Event struct {
+	ID   uint64      `json:"id"`
+	JID  string      `json:"jid"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+	Time time.Time   `json:"time"`
+}
+
+// eventBus Struct Fanning Out Events to Every Subscriber
+type eventBus struct {
+	mu          sync.RWMutex
+	sequence    int
+	subscribers map[int]chan Event
+}
+
+var events = &eventBus{
+	subscribers: make(map[int]chan Event),
+}
+
+// WASubscribe Function Returns a Channel Receiving Every Event
+// and a Function to Cancel The Subscription
+// Events Are Dropped for Subscribers That Do Not Keep Up
+func WASubscribe() (<-chan Event, func()) {
+	return events.subscribe()
+}
+
+func (b *eventBus) subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sequence++
+	id := b.sequence
+	ch := make(chan Event, 64)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+
+			close(ch)
+		})
+	}
+}
+
+func (b *eventBus) publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// WAEventsSince Function Returns The Logged Events of a JID Newer Than id
+func WAEventsSince(jid string, id uint64) ([]Event, error) {
+	logged := make([]Event, 0)
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketEvents, jid)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Seek(storeSequenceKey(id + 1)); k != nil; k, v = c.Next() {
+			var event Event
+
+			err := json.Unmarshal(v, &event)
+			if err != nil {
+				return err
+			}
+
+			logged = append(logged, event)
+		}
+
+		return nil
+	})
+
+	return logged, err
+}
+
+// publishEvent Function Logs an Event Then Fans it Out to The Bus
+// and to The Webhooks of its JID
+func publishEvent(jid string, eventType string, data interface{}) {
+	event := Event{
+		JID:  jid,
+		Type: eventType,
+		Data: data,
+		Time: time.Now(),
+	}
+
+	err := eventLog(&event)
+	if err != nil {
+		svc.Log("error", "event", err.Error())
+	}
+
+	events.publish(event)
+	webhookNotify(event)
+}
+
+// eventLog Function Assigns The Next ID of its JID to an Event and Stores it,
+// Dropping The Events That Fall Out of The Log
+func eventLog(event *Event) error {
+	return svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket, err := storeBucket(tx, bucketEvents, event.JID)
+		if err != nil {
+			return err
+		}
+
+		event.ID, err = bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		err = bucket.Put(storeSequenceKey(event.ID), data)
+		if err != nil {
+			return err
+		}
+
+		if event.ID <= uint64(EventLogSize) {
+			return nil
+		}
+
+		oldest := storeSequenceKey(event.ID - uint64(EventLogSize))
+
+		c := bucket.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) <= 0; k, _ = c.First() {
+			err = c.Delete()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package helper
+
+import "testing"
+
+func TestEventsSince(t *testing.T) {
+	jid := "events-since"
+
+	size := EventLogSize
+	EventLogSize = 3
+	defer func() { EventLogSize = size }()
+
+	for i := 0; i < 5; i++ {
+		publishEvent(jid, EventState, SessionInfo{State: StateConnected})
+	}
+
+	logged, err := WAEventsSince(jid, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logged) != 3 || logged[0].ID != 3 || logged[2].ID != 5 {
+		t.Fatalf("log kept %+v, want events 3 to 5", logged)
+	}
+
+	logged, err = WAEventsSince(jid, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logged) != 1 || logged[0].ID != 5 || logged[0].JID != jid {
+		t.Fatalf("events after 4 are %+v, want event 5", logged)
+	}
+}
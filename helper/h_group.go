@@ -0,0 +1,428 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wajid"
+)
+
+// Group Participant Actions
+const (
+	GroupAdd     = "add"
+	GroupRemove  = "remove"
+	GroupPromote = "promote"
+	GroupDemote  = "demote"
+)
+
+// groupInviteURL is Prefixed to Invite Codes to Build Invite Links
+const groupInviteURL = "https://chat.whatsapp.com/"
+
+// groupListWorkers is How Many Groups Are Fetched at Once When Listing
+const groupListWorkers = 8
+
+// GroupListTimeout is How Long WhatsApp Gets to Answer For Each Listed Group
+var GroupListTimeout = 5 * time.Second
+
+// Group Struct Describing a Group and its Participants
+// Listed Groups That Could Not be Fetched Only Carry Their Error
+type Group struct {
+	JID          string             `json:"jid"`
+	Subject      string             `json:"subject"`
+	Description  string             `json:"description,omitempty"`
+	Owner        string             `json:"owner,omitempty"`
+	Participants []GroupParticipant `json:"participants"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Error        string             `json:"error,omitempty"`
+	ErrorCode    string             `json:"error_code,omitempty"`
+}
+
+// GroupParticipant Struct Describing a Participant of a Group
+type GroupParticipant struct {
+	JID          string `json:"jid"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+// GroupParticipantResult Struct Describing The Outcome of a Participant Action
+type GroupParticipantResult struct {
+	JID    string `json:"jid"`
+	Status int    `json:"status"`
+}
+
+// groupMetaData Struct Describing The Group Metadata Sent by WhatsApp
+type groupMetaData struct {
+	Status       int    `json:"status"`
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	Subject      string `json:"subject"`
+	Creation     int64  `json:"creation"`
+	Desc         string `json:"desc"`
+	Participants []struct {
+		ID           string `json:"id"`
+		IsAdmin      bool   `json:"isAdmin"`
+		IsSuperAdmin bool   `json:"isSuperAdmin"`
+	} `json:"participants"`
+}
+
+// groupAnswer Struct Describing The Answer of WhatsApp to a Group Action
+type groupAnswer struct {
+	Status       int                                     `json:"status"`
+	GID          string                                  `json:"gid"`
+	Participants []map[string]struct{ Code interface{} } `json:"participants"`
+}
+
+// WAGroupList Function Returns Every Group a JID is Known to Belong to,
+// Fetching Them Concurrently. Groups That Could Not be Fetched Are Listed
+// With Their Error Instead of Failing The Whole List
+func WAGroupList(jid string) ([]Group, error) {
+	conn := wac.Get(jid)
+	if conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	chats := make([]Chat, 0)
+	for _, chat := range WAChatList(jid) {
+		if chat.IsGroup {
+			chats = append(chats, chat)
+		}
+	}
+
+	list := make([]Group, len(chats))
+	workers := make(chan struct{}, groupListWorkers)
+
+	var wg sync.WaitGroup
+
+	for i, chat := range chats {
+		wg.Add(1)
+		workers <- struct{}{}
+
+		go func(i int, chat Chat) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			group, err := groupGetWithin(conn, chat.JID, GroupListTimeout)
+			if err != nil {
+				group = Group{JID: chat.JID, Subject: chat.Name, Participants: []GroupParticipant{}, Error: err.Error()}
+				_, group.ErrorCode = WAErrorCode(err)
+			}
+
+			list[i] = group
+		}(i, chat)
+	}
+
+	wg.Wait()
+
+	return list, nil
+}
+
+// WAGroupGet Function Returns a Group of a JID
+func WAGroupGet(jid string, groupID string) (Group, error) {
+	conn, gid, err := groupConn(jid, groupID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	group, err := groupGet(conn, gid)
+	if err != nil {
+		return Group{}, err
+	}
+
+	groupRemember(jid, group)
+
+	return group, nil
+}
+
+// WAGroupCreate Function Creates a Group With a Subject and Participants
+func WAGroupCreate(jid string, subject string, participants []string) (Group, error) {
+	if len(strings.TrimSpace(subject)) == 0 {
+		return Group{}, &waError{kind: ErrInvalidArgument, err: errors.New("group subject is required")}
+	}
+
+	remotes, err := groupParticipants(participants)
+	if err != nil {
+		return Group{}, err
+	}
+
+	conn := wac.Get(jid)
+	if conn == nil {
+		return Group{}, ErrNotConnected
+	}
+
+	data, err := waQuery(conn.CreateGroup(subject, remotes))
+	if err != nil {
+		return Group{}, err
+	}
+
+	var answer groupAnswer
+
+	err = json.Unmarshal(data, &answer)
+	if err != nil {
+		return Group{}, err
+	}
+
+	err = groupStatus(answer.Status)
+	if err != nil {
+		return Group{}, err
+	}
+
+	gid := waJidNormalize(answer.GID)
+
+	group, err := groupGet(conn, gid)
+	if err != nil {
+		// The Group Exists Already, Answer With What is Known About it
+		svc.Log("warn", "group", "failed to fetch created group "+gid+": "+err.Error())
+		group = Group{JID: gid, Subject: subject, Participants: []GroupParticipant{}, CreatedAt: time.Now()}
+	}
+
+	groupRemember(jid, group)
+
+	return group, nil
+}
+
+// WAGroupParticipants Function Adds, Removes, Promotes or Demotes
+// Participants of a Group, Reporting The Outcome For Each of Them
+func WAGroupParticipants(jid string, groupID string, action string, participants []string) ([]GroupParticipantResult, error) {
+	remotes, err := groupParticipants(participants)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(remotes) == 0 {
+		return nil, &waError{kind: ErrInvalidArgument, err: errors.New("participants are required")}
+	}
+
+	conn, gid, err := groupConn(jid, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch <-chan string
+
+	switch action {
+	case GroupAdd:
+		ch, err = conn.AddMember(gid, remotes)
+	case GroupRemove:
+		ch, err = conn.RemoveMember(gid, remotes)
+	case GroupPromote:
+		ch, err = conn.SetAdmin(gid, remotes)
+	case GroupDemote:
+		ch, err = conn.RemoveAdmin(gid, remotes)
+	default:
+		return nil, &waError{kind: ErrInvalidArgument, err: errors.New("unknown participant action " + action)}
+	}
+
+	answer, err := groupAction(ch, err)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]GroupParticipantResult, 0, len(remotes))
+	for _, participant := range answer.Participants {
+		for id, outcome := range participant {
+			status, _ := strconv.Atoi(fmt.Sprint(outcome.Code))
+			results = append(results, GroupParticipantResult{JID: waJidNormalize(id), Status: status})
+		}
+	}
+
+	return results, nil
+}
+
+// WAGroupSubject Function Changes The Subject of a Group
+func WAGroupSubject(jid string, groupID string, subject string) error {
+	if len(strings.TrimSpace(subject)) == 0 {
+		return &waError{kind: ErrInvalidArgument, err: errors.New("group subject is required")}
+	}
+
+	conn, gid, err := groupConn(jid, groupID)
+	if err != nil {
+		return err
+	}
+
+	_, err = groupAction(conn.UpdateGroupSubject(subject, gid))
+
+	return err
+}
+
+// WAGroupInviteLink Function Returns The Invite Link of a Group
+func WAGroupInviteLink(jid string, groupID string) (string, error) {
+	conn, gid, err := groupConn(jid, groupID)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := conn.GroupInviteLink(gid)
+	if err != nil {
+		// The Library Reports The Status of a Failed Request in The Message
+		message := err.Error()
+
+		switch {
+		case strings.Contains(message, "404"):
+			return "", fmt.Errorf("group %w", ErrNotFound)
+		case strings.Contains(message, "401"), strings.Contains(message, "403"):
+			return "", &waError{kind: ErrForbidden, err: err}
+		}
+
+		return "", waErrorWrap(err)
+	}
+
+	return groupInviteURL + code, nil
+}
+
+// WAGroupLeave Function Leaves a Group
+func WAGroupLeave(jid string, groupID string) error {
+	conn, gid, err := groupConn(jid, groupID)
+	if err != nil {
+		return err
+	}
+
+	_, err = groupAction(conn.LeaveGroup(gid))
+	if err != nil {
+		return err
+	}
+
+	rosterChatRemove(jid, gid)
+
+	return nil
+}
+
+// groupRemember Function Records a JID Belongs to a Group in its Chats
+func groupRemember(jid string, group Group) {
+	rosterChatUpdate(jid, group.JID, func(chat *Chat) bool {
+		changed := chat.Name != group.Subject
+		chat.Name = group.Subject
+
+		return changed
+	})
+}
+
+// groupConn Function Returns The Client of a JID and The Group JID of an ID
+func groupConn(jid string, groupID string) (Client, string, error) {
+	remote, err := wajid.Parse(groupID)
+	if err != nil || !remote.IsGroup() {
+		return nil, "", &waError{kind: ErrInvalidRecipient, err: errors.New("group id is invalid")}
+	}
+
+	conn := wac.Get(jid)
+	if conn == nil {
+		return nil, "", ErrNotConnected
+	}
+
+	return conn, remote.String(), nil
+}
+
+// groupParticipants Function Normalizes Phone Numbers or JIDs of Participants
+func groupParticipants(participants []string) ([]string, error) {
+	remotes := make([]string, 0, len(participants))
+
+	for _, participant := range participants {
+		remote, err := wajid.Parse(participant)
+		if err == nil && remote.Server != wajid.ServerUser {
+			err = errors.New("recipient is not a phone number")
+		}
+
+		if err != nil {
+			return nil, &waError{kind: ErrInvalidRecipient, err: errors.New("participant " + participant + ": " + err.Error())}
+		}
+
+		remotes = append(remotes, remote.String())
+	}
+
+	return remotes, nil
+}
+
+// groupGet Function Fetches The Metadata of a Group
+func groupGet(conn Client, gid string) (Group, error) {
+	return groupGetWithin(conn, gid, waQueryTimeout)
+}
+
+// groupGetWithin Function Fetches The Metadata of a Group, Waiting at Most
+// timeout For WhatsApp to Answer
+func groupGetWithin(conn Client, gid string, timeout time.Duration) (Group, error) {
+	ch, err := conn.GetGroupMetaData(gid)
+
+	data, err := waQueryWithin(ch, err, timeout)
+	if err != nil {
+		return Group{}, err
+	}
+
+	var meta groupMetaData
+
+	err = json.Unmarshal(data, &meta)
+	if err != nil {
+		return Group{}, err
+	}
+
+	if meta.Status != 0 {
+		err = groupStatus(meta.Status)
+		if err != nil {
+			return Group{}, err
+		}
+	}
+
+	group := Group{
+		JID:          gid,
+		Subject:      meta.Subject,
+		Description:  meta.Desc,
+		Participants: make([]GroupParticipant, 0, len(meta.Participants)),
+		CreatedAt:    time.Unix(meta.Creation, 0),
+	}
+
+	if len(meta.Owner) > 0 {
+		group.Owner = waJidNormalize(meta.Owner)
+	}
+
+	for _, participant := range meta.Participants {
+		group.Participants = append(group.Participants, GroupParticipant{
+			JID:          waJidNormalize(participant.ID),
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		})
+	}
+
+	sort.Slice(group.Participants, func(i, j int) bool {
+		return group.Participants[i].JID < group.Participants[j].JID
+	})
+
+	return group, nil
+}
+
+// groupAction Function Waits For The Answer to a Group Action
+func groupAction(ch <-chan string, err error) (groupAnswer, error) {
+	var answer groupAnswer
+
+	data, err := waQuery(ch, err)
+	if err != nil {
+		return answer, err
+	}
+
+	err = json.Unmarshal(data, &answer)
+	if err != nil {
+		return answer, err
+	}
+
+	return answer, groupStatus(answer.Status)
+}
+
+// groupStatus Function Returns The Error Described by The Status of an Answer
+func groupStatus(status int) error {
+	switch {
+	case status >= 200 && status < 300:
+		return nil
+	case status == 401, status == 403:
+		return &waError{kind: ErrForbidden, err: errors.New("not allowed to manage the group")}
+	case status == 404:
+		return fmt.Errorf("group %w", ErrNotFound)
+	}
+
+	return fmt.Errorf("group action failed with status %d", status)
+}
@@ -0,0 +1,146 @@
+package helper
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+)
+
+func TestGroupLifecycle(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	jid := "grouping"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	group, err := WAGroupCreate(jid, "Friends", []string{"6281111111111"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.Subject != "Friends" || len(group.Participants) != 2 {
+		t.Fatalf("created group is %+v", group)
+	}
+
+	results, err := WAGroupParticipants(jid, group.JID, GroupAdd, []string{"+62 822-2222-2222"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].JID != "6282222222222@s.whatsapp.net" || results[0].Status != 200 {
+		t.Fatalf("adding a participant answered %+v", results)
+	}
+
+	err = WAGroupSubject(jid, group.JID, "Family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := WAGroupList(jid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list) != 1 || list[0].Subject != "Family" || len(list[0].Participants) != 3 {
+		t.Fatalf("group list is %+v", list)
+	}
+
+	link, err := WAGroupInviteLink(jid, group.JID)
+	if err != nil || !strings.HasPrefix(link, groupInviteURL) {
+		t.Fatalf("invite link is %q, %v", link, err)
+	}
+
+	_, err = WAGroupParticipants(jid, group.JID, "kick", []string{"6281111111111"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("unknown action returned %v, want %v", err, ErrInvalidArgument)
+	}
+
+	err = WAGroupLeave(jid, group.JID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = WAGroupGet(jid, group.JID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("left group returned %v, want %v", err, ErrNotFound)
+	}
+
+	_, err = WAGroupGet(jid, "6281111111111")
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("phone number as group returned %v, want %v", err, ErrInvalidRecipient)
+	}
+}
+
+func TestGroupListPartial(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	jid := "grouplisting"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	fetched := "6281111111111-1500000000@g.us"
+	gone := "6281111111111-1500000001@g.us"
+	hanging := "6281111111111-1500000002@g.us"
+
+	for _, gid := range []string{fetched, gone, hanging} {
+		rosterChatUpdate(jid, gid, func(chat *Chat) bool {
+			chat.Name = "Listed"
+			return true
+		})
+	}
+
+	// The Hanging Group Never Gets an Answer
+	client.GroupMetaDataFunc = func(gid string) (<-chan string, error) {
+		ch := make(chan string, 1)
+
+		switch gid {
+		case fetched:
+			ch <- `{"id":"` + gid + `","subject":"Fetched","creation":1500000000,"participants":[]}`
+		case gone:
+			ch <- `{"status":404}`
+		}
+
+		return ch, nil
+	}
+
+	timeout := GroupListTimeout
+	GroupListTimeout = 50 * time.Millisecond
+	defer func() { GroupListTimeout = timeout }()
+
+	list, err := WAGroupList(jid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := make(map[string]string)
+	for _, group := range list {
+		codes[group.JID] = group.ErrorCode
+	}
+
+	if len(list) != 3 || codes[fetched] != "" || codes[gone] != "not_found" || codes[hanging] != "send_timeout" {
+		t.Fatalf("group list is %+v", list)
+	}
+
+	// Listing Leaves The Chats of Groups it Could Not Fetch in Place
+	chats := 0
+	for _, chat := range WAChatList(jid) {
+		if chat.IsGroup {
+			chats++
+		}
+	}
+
+	if chats != 3 {
+		t.Errorf("listing groups left %d group chats, want 3", chats)
+	}
+}
@@ -0,0 +1,390 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Message Directions
+const (
+	HistoryInbound  = "inbound"
+	HistoryOutbound = "outbound"
+)
+
+// History Page Size Bounds
+const (
+	HistoryPageSize = 50
+	HistoryPageMax  = 500
+)
+
+// Store Bucket Keeping Message History, Nested by JID, Then Either by Chat
+// With Keys Sorted by Time or by Message ID Pointing at Those Keys
+const (
+	bucketHistory = "history"
+	historyChats  = "chats"
+	historyIDs    = "ids"
+)
+
+// HistoryMessage Struct Describing a Message Sent or Received by an Account
+type HistoryMessage struct {
+	ID          string    `json:"id"`
+	Chat        string    `json:"chat"`
+	Direction   string    `json:"direction"`
+	Participant string    `json:"participant,omitempty"`
+	Type        string    `json:"type"`
+	Text        string    `json:"text,omitempty"`
+	Media       string    `json:"media,omitempty"`
+	MediaType   string    `json:"media_type,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// HistoryQuery Struct Describing Which Messages of a Chat to Return,
+// Most Recent First, Starting Before The Cursor of a Previous Page
+type HistoryQuery struct {
+	Since  time.Time
+	Until  time.Time
+	Cursor string
+	Limit  int
+}
+
+// HistoryPage Struct Describing a Page of Messages and The Cursor of
+// The Next One, Empty on The Last Page
+type HistoryPage struct {
+	Messages   []HistoryMessage `json:"messages"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// WAHistory Function Returns a Page of The Messages of a Chat of a JID
+func WAHistory(jid string, chatID string, query HistoryQuery) (HistoryPage, error) {
+	page := HistoryPage{Messages: make([]HistoryMessage, 0)}
+
+	chat, err := waRemoteJid(chatID)
+	if err != nil {
+		return page, err
+	}
+
+	seek, err := historySeek(&query)
+	if err != nil {
+		return page, err
+	}
+
+	err = svc.Store.View(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketHistory, jid, historyChats, chat)
+		if bucket == nil {
+			return nil
+		}
+
+		return historyWalk(bucket, seek, query, &page, func(k, v []byte) (*HistoryMessage, error) {
+			var msg HistoryMessage
+
+			return &msg, json.Unmarshal(v, &msg)
+		})
+	})
+
+	return page, err
+}
+
+// historySeek Function Validates The Limit and Cursor of a Query, Returning
+// The Key to Walk Back From, Nil to Start at The Most Recent Message
+func historySeek(query *HistoryQuery) ([]byte, error) {
+	switch {
+	case query.Limit == 0:
+		query.Limit = HistoryPageSize
+	case query.Limit < 0 || query.Limit > HistoryPageMax:
+		return nil, &waError{kind: ErrInvalidArgument, err: errors.New("limit must be between 1 and 500")}
+	}
+
+	var seek []byte
+
+	if len(query.Cursor) > 0 {
+		var err error
+
+		seek, err = base64.RawURLEncoding.DecodeString(query.Cursor)
+		if err != nil || len(seek) <= 8 {
+			return nil, &waError{kind: ErrInvalidArgument, err: errors.New("cursor is invalid")}
+		}
+	}
+
+	if !query.Until.IsZero() {
+		until := historyTimeKey(query.Until.Add(time.Nanosecond))
+		if seek == nil || bytes.Compare(until, seek) < 0 {
+			seek = until
+		}
+	}
+
+	return seek, nil
+}
+
+// historyWalk Function Fills a Page Walking Back From a Key Through a Bucket
+// With Keys Starting With The Timestamp, read Returns Nil to Skip an Entry
+func historyWalk(bucket *bolt.Bucket, seek []byte, query HistoryQuery, page *HistoryPage, read func(k, v []byte) (*HistoryMessage, error)) error {
+	c := bucket.Cursor()
+
+	var k, v []byte
+
+	if seek == nil {
+		k, v = c.Last()
+	} else {
+		k, v = c.Seek(seek)
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+	}
+
+	for ; k != nil; k, v = c.Prev() {
+		if !query.Since.IsZero() && historyKeyTime(k).Before(query.Since) {
+			break
+		}
+
+		msg, err := read(k, v)
+		if err != nil {
+			return err
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		if len(page.Messages) == query.Limit {
+			page.NextCursor = base64.RawURLEncoding.EncodeToString(historyCursorKey(page.Messages[len(page.Messages)-1]))
+			break
+		}
+
+		page.Messages = append(page.Messages, *msg)
+	}
+
+	return nil
+}
+
+// WAHistoryMedia Function Returns The File Holding The Media of a Message of
+// a JID and its Media Type, Either Received or Still Waiting to be Sent
+func WAHistoryMedia(jid string, id string) (string, string, error) {
+	var media, mediaType string
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		_, _, msg, err := historyLocate(tx, jid, id)
+		if err != nil {
+			return err
+		}
+
+		if len(msg.Media) > 0 {
+			media, mediaType = msg.Media, msg.MediaType
+			return nil
+		}
+
+		queued, found, err := queueGet(tx, jid, id)
+		if err != nil || !found || len(queued.MediaType) == 0 {
+			return err
+		}
+
+		media, mediaType = queueMediaFile(id), queued.MediaType
+
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(media) == 0 {
+		return "", "", fmt.Errorf("media %w", ErrNotFound)
+	}
+
+	// Media of Sent Messages is Removed Once They Are Sent
+	if _, err := os.Stat(media); err != nil {
+		return "", "", fmt.Errorf("media %w", ErrNotFound)
+	}
+
+	return media, mediaType, nil
+}
+
+// historyReceived Function Records a Message Received by a JID, or Sent
+// From Another Device of The Account
+func historyReceived(jid string, msgType string, info whatsapp.MessageInfo, text string) {
+	if len(info.Id) == 0 {
+		return
+	}
+
+	msg := HistoryMessage{
+		ID:        info.Id,
+		Chat:      waJidNormalize(info.RemoteJid),
+		Direction: HistoryInbound,
+		Type:      msgType,
+		Text:      text,
+		Timestamp: time.Unix(int64(info.Timestamp), 0),
+	}
+
+	if info.FromMe {
+		msg.Direction = HistoryOutbound
+	}
+
+	if len(info.SenderJid) > 0 {
+		msg.Participant = waJidNormalize(info.SenderJid)
+	}
+
+	if info.Timestamp == 0 {
+		msg.Timestamp = time.Now()
+	}
+
+	historyPut(jid, msg)
+}
+
+// historySent Function Records a Message a JID Sent From The Queue
+func historySent(jid string, queued QueueMessage) {
+	msg := HistoryMessage{
+		ID:        queued.ID,
+		Chat:      queued.To,
+		Direction: HistoryOutbound,
+		Type:      queued.Type,
+		Text:      queued.Text,
+		MediaType: queued.MediaType,
+		FileName:  queued.FileName,
+		Timestamp: queued.UpdatedAt,
+	}
+
+	switch queued.Type {
+	case "location", "contact":
+		msg.Text = queued.Name
+	}
+
+	historyPut(jid, msg)
+}
+
+// historyMedia Function Records Where The Media of a Received Message is Stored
+func historyMedia(jid string, id string, media string, mediaType string, fileName string) {
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket, key, msg, err := historyLocate(tx, jid, id)
+		if err != nil || bucket == nil {
+			return err
+		}
+
+		msg.Media = media
+		msg.MediaType = mediaType
+		msg.FileName = fileName
+
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		err = bucket.Put(key, data)
+		if err != nil {
+			return err
+		}
+
+		// The File Name Was Not Known When The Message Was Indexed
+		return searchIndex(tx, jid, msg, key)
+	})
+	if err != nil {
+		svc.Log("error", "history", err.Error())
+	}
+}
+
+// historyPut Function Stores a Message of a JID Unless it is Already
+// Known, as Messages Are Replayed After Every Reconnect
+func historyPut(jid string, msg HistoryMessage) {
+	msg.StoredAt = time.Now()
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		svc.Log("error", "history", err.Error())
+		return
+	}
+
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		ids, err := storeBucket(tx, bucketHistory, jid, historyIDs)
+		if err != nil {
+			return err
+		}
+
+		if ids.Get([]byte(msg.ID)) != nil {
+			return nil
+		}
+
+		chat, err := storeBucket(tx, bucketHistory, jid, historyChats, msg.Chat)
+		if err != nil {
+			return err
+		}
+
+		key := historyCursorKey(msg)
+
+		err = chat.Put(key, data)
+		if err != nil {
+			return err
+		}
+
+		err = searchIndex(tx, jid, msg, key)
+		if err != nil {
+			return err
+		}
+
+		return ids.Put([]byte(msg.ID), append(append([]byte(msg.Chat), 0), key...))
+	})
+	if err != nil {
+		svc.Log("error", "history", err.Error())
+	}
+}
+
+// historyLocate Function Finds a Message of a JID by its ID, Returning
+// The Chat Bucket and Key it is Stored Under, or a Nil Bucket When Missing
+func historyLocate(tx *bolt.Tx, jid string, id string) (*bolt.Bucket, []byte, HistoryMessage, error) {
+	var msg HistoryMessage
+
+	ids := storeBucketRead(tx, bucketHistory, jid, historyIDs)
+	if ids == nil {
+		return nil, nil, msg, nil
+	}
+
+	location := ids.Get([]byte(id))
+
+	i := bytes.IndexByte(location, 0)
+	if i < 0 {
+		return nil, nil, msg, nil
+	}
+
+	bucket := storeBucketRead(tx, bucketHistory, jid, historyChats, string(location[:i]))
+	if bucket == nil {
+		return nil, nil, msg, nil
+	}
+
+	key := append([]byte(nil), location[i+1:]...)
+
+	data := bucket.Get(key)
+	if data == nil {
+		return nil, nil, msg, nil
+	}
+
+	return bucket, key, msg, json.Unmarshal(data, &msg)
+}
+
+// historyCursorKey Function Returns The Key a Message is Stored Under,
+// Its Timestamp Followed by its ID to Keep Messages at The Same Time Apart
+func historyCursorKey(msg HistoryMessage) []byte {
+	return append(historyTimeKey(msg.Timestamp), msg.ID...)
+}
+
+// historyTimeKey Function Encodes a Time as a Sortable Key Prefix
+func historyTimeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+
+	return key
+}
+
+// historyKeyTime Function Decodes The Time a Key Starts With
+func historyKeyTime(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
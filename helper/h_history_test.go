@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestHistoryMediaScope(t *testing.T) {
+	file := filepath.Join(testDir, "MEDIA1.jpg")
+
+	err := ioutil.WriteFile(file, []byte("jpeg"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := whatsapp.MessageInfo{Id: "MEDIA1", RemoteJid: "6281234567890@s.whatsapp.net", Timestamp: 1600000000}
+
+	historyReceived("media-owner", "image", info, "")
+	historyMedia("media-owner", info.Id, file, "image/jpeg", "")
+
+	media, mediaType, err := WAHistoryMedia("media-owner", info.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if media != file || mediaType != "image/jpeg" {
+		t.Fatalf("got %s as %s, want %s as image/jpeg", media, mediaType, file)
+	}
+
+	// Media is Only Served to The Account That Received it
+	for _, id := range []string{info.Id, "*", "MEDIA*"} {
+		_, _, err = WAHistoryMedia("media-other", id)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("media %s of another account answered %v", id, err)
+		}
+	}
+}
+
+func TestHistoryWalkBoundaries(t *testing.T) {
+	jid := "history-walk"
+	chat := "6281234567890@s.whatsapp.net"
+	base := time.Unix(1600000000, 0)
+
+	// Messages M0 to M4 a Second Apart, M2B Sharing The Time of M2
+	for i, id := range []string{"M0", "M1", "M2", "M3", "M4"} {
+		historyPut(jid, HistoryMessage{ID: id, Chat: chat, Direction: HistoryInbound, Type: "text", Timestamp: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	historyPut(jid, HistoryMessage{ID: "M2B", Chat: chat, Direction: HistoryInbound, Type: "text", Timestamp: base.Add(2 * time.Second)})
+
+	at := func(i int) time.Time {
+		return base.Add(time.Duration(i) * time.Second)
+	}
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		pages [][]string
+	}{
+		{"pages", HistoryQuery{Limit: 2}, [][]string{{"M4", "M3"}, {"M2B", "M2"}, {"M1", "M0"}}},
+		{"page of the exact size", HistoryQuery{Limit: 6}, [][]string{{"M4", "M3", "M2B", "M2", "M1", "M0"}}},
+		{"page splitting a second", HistoryQuery{Limit: 3}, [][]string{{"M4", "M3", "M2B"}, {"M2", "M1", "M0"}}},
+		{"since is inclusive", HistoryQuery{Since: at(3)}, [][]string{{"M4", "M3"}}},
+		{"until is inclusive", HistoryQuery{Until: at(1)}, [][]string{{"M1", "M0"}}},
+		{"since and until of one second", HistoryQuery{Since: at(2), Until: at(2)}, [][]string{{"M2B", "M2"}}},
+		{"until paged", HistoryQuery{Until: at(3), Limit: 2}, [][]string{{"M3", "M2B"}, {"M2", "M1"}, {"M0"}}},
+		{"since paged", HistoryQuery{Since: at(1), Limit: 2}, [][]string{{"M4", "M3"}, {"M2B", "M2"}, {"M1"}}},
+		{"since after every message", HistoryQuery{Since: at(5)}, [][]string{{}}},
+		{"until before every message", HistoryQuery{Until: at(-1)}, [][]string{{}}},
+	}
+
+	for _, test := range tests {
+		query := test.query
+
+		for i, want := range test.pages {
+			page, err := WAHistory(jid, chat, query)
+			if err != nil {
+				t.Fatalf("%s: page %d returned %v", test.name, i, err)
+			}
+
+			got := make([]string, 0, len(page.Messages))
+			for _, msg := range page.Messages {
+				got = append(got, msg.ID)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: page %d is %v, want %v", test.name, i, got, want)
+			}
+
+			last := i == len(test.pages)-1
+			if last != (len(page.NextCursor) == 0) {
+				t.Errorf("%s: page %d has next cursor %q", test.name, i, page.NextCursor)
+			}
+
+			query.Cursor = page.NextCursor
+		}
+	}
+
+	// An Until Before The Cursor Takes Precedence, an Until After it Does Not
+	page, err := WAHistory(jid, chat, HistoryQuery{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		until time.Time
+		first string
+	}{
+		{at(1), "M1"},
+		{at(4), "M2B"},
+	} {
+		next, err := WAHistory(jid, chat, HistoryQuery{Cursor: page.NextCursor, Until: test.until, Limit: 1})
+		if err != nil || len(next.Messages) != 1 || next.Messages[0].ID != test.first {
+			t.Errorf("cursor with until %v returned %+v, %v", test.until, next.Messages, err)
+		}
+	}
+
+	for _, query := range []HistoryQuery{{Cursor: "not a cursor"}, {Cursor: "AAAA"}, {Limit: HistoryPageMax + 1}, {Limit: -1}} {
+		_, err := WAHistory(jid, chat, query)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("query %+v returned %v, want %v", query, err, ErrInvalidArgument)
+		}
+	}
+}
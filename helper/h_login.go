@@ -0,0 +1,321 @@
+package helper
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Login Session Statuses
+const (
+	LoginPending   = "pending"
+	LoginSuccess   = "success"
+	LoginTimeout   = "timeout"
+	LoginRejected  = "rejected"
+	LoginCancelled = "cancelled"
+)
+
+// LoginMaxDuration is How Long a Login Keeps Refreshing its QR Code
+// Before it Gives up Waiting For a Scan
+var LoginMaxDuration = 5 * time.Minute
+
+// loginKeep is How Long a Finished Login Can Still be Polled
+const loginKeep = 10 * time.Minute
+
+// LoginSession Struct Describing a QR Code Login in Progress
+type LoginSession struct {
+	ID        string    `json:"id"`
+	JID       string    `json:"jid"`
+	Status    string    `json:"status"`
+	QRCode    string    `json:"qrcode,omitempty"`
+	QRCount   int       `json:"qr_count"`
+	Error     string    `json:"error,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// login Struct Tracking a Login Session and Signalling its Changes
+type login struct {
+	session LoginSession
+	changed chan struct{}
+	cancel  chan struct{}
+}
+
+var logins = struct {
+	sync.Mutex
+	m map[string]*login
+}{m: make(map[string]*login)}
+
+// WALoginStart Function Starts a Login of a JID Running in The Background,
+// Restoring The Stored Session or Refreshing QR Codes Until One is Scanned.
+// A JID With a Pending Login Gets That Login Back
+func WALoginStart(jid string, file string, timeout int) LoginSession {
+	logins.Lock()
+	defer logins.Unlock()
+
+	now := time.Now()
+
+	for id, l := range logins.m {
+		if l.session.JID == jid && l.session.Status == LoginPending {
+			return l.session
+		}
+
+		if l.session.Status != LoginPending && now.Sub(l.session.UpdatedAt) > loginKeep {
+			delete(logins.m, id)
+		}
+	}
+
+	l := &login{
+		session: LoginSession{
+			ID:        webhookID(),
+			JID:       jid,
+			Status:    LoginPending,
+			ExpiresAt: now.Add(LoginMaxDuration),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		changed: make(chan struct{}),
+		cancel:  make(chan struct{}),
+	}
+	logins.m[l.session.ID] = l
+
+	go loginRun(l, file, timeout)
+
+	return l.session
+}
+
+// WALoginGet Function Returns a Login Session of a JID
+func WALoginGet(jid string, id string) (LoginSession, error) {
+	logins.Lock()
+	defer logins.Unlock()
+
+	l, ok := logins.m[id]
+	if !ok || l.session.JID != jid {
+		return LoginSession{}, fmt.Errorf("login %w", ErrNotFound)
+	}
+
+	return l.session, nil
+}
+
+// WALoginWait Function Waits Until a Login Session of a JID Has a QR Code
+// or is Finished, Returning The Session as it is Once wait Has Passed
+func WALoginWait(jid string, id string, wait time.Duration) (LoginSession, error) {
+	return loginWait(jid, id, wait, func(session LoginSession) bool {
+		return session.Status != LoginPending || len(session.QRCode) > 0
+	})
+}
+
+// loginWait Function Waits Until a Login Session of a JID is Ready
+func loginWait(jid string, id string, wait time.Duration, ready func(LoginSession) bool) (LoginSession, error) {
+	expired := time.After(wait)
+
+	for {
+		logins.Lock()
+		l, ok := logins.m[id]
+		if !ok || l.session.JID != jid {
+			logins.Unlock()
+			return LoginSession{}, fmt.Errorf("login %w", ErrNotFound)
+		}
+
+		session, changed := l.session, l.changed
+		logins.Unlock()
+
+		if ready(session) {
+			return session, nil
+		}
+
+		select {
+		case <-changed:
+		case <-expired:
+			return session, nil
+		}
+	}
+}
+
+// WALoginCancel Function Cancels a Pending Login Session of a JID
+func WALoginCancel(jid string, id string) (LoginSession, error) {
+	logins.Lock()
+	l, ok := logins.m[id]
+	if !ok || l.session.JID != jid {
+		logins.Unlock()
+		return LoginSession{}, fmt.Errorf("login %w", ErrNotFound)
+	}
+
+	if l.session.Status != LoginPending {
+		logins.Unlock()
+		return l.session, &waError{kind: ErrConflict, err: errors.New("login is not pending")}
+	}
+
+	select {
+	case <-l.cancel:
+	default:
+		close(l.cancel)
+	}
+	logins.Unlock()
+
+	return loginWait(jid, id, time.Minute, func(session LoginSession) bool {
+		return session.Status != LoginPending
+	})
+}
+
+// loginForget Function Cancels The Pending Login of a JID and Drops
+// Every Login Session of it
+func loginForget(jid string) {
+	var pending []string
+
+	logins.Lock()
+	for id, l := range logins.m {
+		if l.session.JID == jid && l.session.Status == LoginPending {
+			pending = append(pending, id)
+		}
+	}
+	logins.Unlock()
+
+	for _, id := range pending {
+		_, _ = WALoginCancel(jid, id)
+	}
+
+	logins.Lock()
+	for id, l := range logins.m {
+		if l.session.JID == jid {
+			delete(logins.m, id)
+		}
+	}
+	logins.Unlock()
+}
+
+// loginRun Function Drives a Login Session Until it Reaches an Outcome
+func loginRun(l *login, file string, timeout int) {
+	jid := l.session.JID
+
+	session, err := WASessionLoad(file)
+	if err == nil {
+		err = WAInit(jid, timeout)
+		if err == nil {
+			err = WASessionRestore(jid, file, session)
+		}
+
+		if err == nil {
+			waSupervise(jid, file, timeout)
+			loginUpdate(l, LoginSuccess, "", nil)
+			return
+		}
+	}
+
+	deadline := time.NewTimer(time.Until(l.session.ExpiresAt))
+	defer deadline.Stop()
+
+	for {
+		err = WAInit(jid, timeout)
+		if err != nil {
+			loginUpdate(l, LoginRejected, "", err)
+			return
+		}
+
+		conn := wac.Get(jid)
+		if conn == nil {
+			loginUpdate(l, LoginRejected, "", ErrNotConnected)
+			return
+		}
+
+		qr := make(chan string, 1)
+		result := make(chan error, 1)
+
+		wac.SetState(jid, StateAwaitingQR)
+
+		go func() {
+			result <- WASessionLogin(jid, file, qr)
+		}()
+
+		status, err := loginAwait(l, qr, result, deadline.C)
+		if status == LoginSuccess {
+			waSupervise(jid, file, timeout)
+			loginUpdate(l, LoginSuccess, "", nil)
+			return
+		}
+
+		// Drop The Connection so The Next Attempt Starts With a Fresh QR Code
+		conn.RemoveHandlers()
+		_ = conn.Disconnect()
+		if wac.Get(jid) == conn {
+			wac.Remove(jid, StateDisconnected)
+		}
+
+		if status == LoginPending && time.Now().After(l.session.ExpiresAt) {
+			status = LoginTimeout
+		}
+
+		if status != LoginPending {
+			loginUpdate(l, status, "", err)
+			return
+		}
+	}
+}
+
+// loginAwait Function Publishes Every QR Code of a Login Attempt Until The
+// Attempt Ends, Returning LoginPending When The QR Code Expired Unscanned
+// and a New Attempt Should be Made
+func loginAwait(l *login, qr <-chan string, result <-chan error, deadline <-chan time.Time) (string, error) {
+	for {
+		select {
+		case code := <-qr:
+			png, err := qrcode.Encode(code, qrcode.Medium, 256)
+			if err != nil {
+				return LoginRejected, err
+			}
+
+			loginUpdate(l, LoginPending, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), nil)
+		case err := <-result:
+			if err == nil {
+				return LoginSuccess, nil
+			}
+
+			if errors.Is(waErrorWrap(err), ErrLoginTimeout) {
+				return LoginPending, err
+			}
+
+			return LoginRejected, err
+		case <-l.cancel:
+			return LoginCancelled, nil
+		case <-deadline:
+			return LoginTimeout, ErrLoginTimeout
+		}
+	}
+}
+
+// loginUpdate Function Records a Change of a Login Session, Wakes its
+// Waiters and Publishes it as a Login Event
+func loginUpdate(l *login, status string, qrCode string, err error) {
+	logins.Lock()
+
+	l.session.Status = status
+	l.session.UpdatedAt = time.Now()
+
+	if len(qrCode) > 0 {
+		l.session.QRCode = qrCode
+		l.session.QRCount++
+	}
+
+	if status != LoginPending {
+		l.session.QRCode = ""
+	}
+
+	l.session.Error = ""
+	if err != nil {
+		l.session.Error = err.Error()
+	}
+
+	session := l.session
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	logins.Unlock()
+
+	publishEvent(session.JID, EventLogin, session)
+}
@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestLoginRefreshAndCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	newClient := NewClient
+	defer func() { NewClient = newClient }()
+
+	// The First QR Code Expires Unscanned, The Second One Waits For a Scan
+	var attempts int32
+	NewClient = func(timeout int) (Client, error) {
+		client := wafake.NewClient()
+		client.LoginFunc = func(qr chan<- string) (whatsapp.Session, error) {
+			qr <- "fake-qr-code"
+
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return whatsapp.Session{}, errors.New("qr code scan timed out")
+			}
+
+			<-release
+			return whatsapp.Session{}, errors.New("qr code scan timed out")
+		}
+
+		return client, nil
+	}
+
+	jid := "login-refresh"
+
+	login := WALoginStart(jid, filepath.Join(testDir, jid+".gob"), 5)
+	testEventually(t, func() bool {
+		login, _ = WALoginGet(jid, login.ID)
+		return login.QRCount == 2
+	})
+
+	if login.Status != LoginPending || len(login.QRCode) == 0 {
+		t.Fatalf("refreshed login is %+v", login)
+	}
+
+	if again := WALoginStart(jid, filepath.Join(testDir, jid+".gob"), 5); again.ID != login.ID {
+		t.Fatalf("pending login was started again as %v", again.ID)
+	}
+
+	login, err := WALoginCancel(jid, login.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if login.Status != LoginCancelled || len(login.QRCode) > 0 {
+		t.Fatalf("cancelled login is %+v", login)
+	}
+
+	_, err = WALoginCancel(jid, login.ID)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("cancelling a finished login returned %v, want %v", err, ErrConflict)
+	}
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Outbound Media Message Types
+var mediaTypes = []string{"image", "document", "audio", "video", "sticker"}
+
+// WAMediaTypes Function Returns The Media Message Types That Can be Sent
+func WAMediaTypes() []string {
+	return append([]string(nil), mediaTypes...)
+}
+
+// WAMediaSniff Function Detects The MIME Type of a Media Stream From its Content
+// Falling Back to The File Extension for Generic Containers, Then Checks it
+// Fits The Message Type. The Stream is Rewound Before Returning
+func WAMediaSniff(msgType string, stream io.ReadSeeker, fileName string) (string, error) {
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(stream, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	_, err = stream.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	mediaType := http.DetectContentType(head[:n])
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+
+	switch mediaType {
+	case "application/octet-stream", "application/zip", "text/plain":
+		if extType := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))); len(extType) > 0 {
+			mediaType = extType
+		}
+	case "application/ogg":
+		if msgType == "audio" {
+			mediaType = "audio/ogg; codecs=opus"
+		}
+	}
+
+	switch msgType {
+	case "image", "audio", "video":
+		if !strings.HasPrefix(mediaType, msgType+"/") {
+			return "", &waError{kind: ErrInvalidArgument, err: errors.New(msgType + " has unsupported content type " + mediaType)}
+		}
+	case "sticker":
+		if mediaType != "image/webp" {
+			return "", &waError{kind: ErrInvalidArgument, err: errors.New("sticker has unsupported content type " + mediaType)}
+		}
+	case "document":
+	default:
+		return "", &waError{kind: ErrInvalidArgument, err: errors.New("unknown message type " + msgType)}
+	}
+
+	return mediaType, nil
+}
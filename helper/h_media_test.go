@@ -0,0 +1,60 @@
+package helper
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestMediaSniff(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	webp := "RIFF\x00\x00\x00\x00WEBPVP8 "
+	ogg := "OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00"
+
+	tests := []struct {
+		msgType   string
+		content   string
+		fileName  string
+		mediaType string
+		fails     bool
+	}{
+		{msgType: "image", content: png, fileName: "photo.png", mediaType: "image/png"},
+		{msgType: "image", content: "%PDF-1.4", fileName: "photo.png", fails: true},
+		{msgType: "document", content: png, fileName: "scan.png", mediaType: "image/png"},
+		{msgType: "document", content: "%PDF-1.4", fileName: "report.pdf", mediaType: "application/pdf"},
+		{msgType: "document", content: "a,b\n1,2\n", fileName: "table.csv", mediaType: "text/csv"},
+		{msgType: "audio", content: ogg, fileName: "voice.ogg", mediaType: "audio/ogg; codecs=opus"},
+		{msgType: "audio", content: png, fileName: "voice.ogg", fails: true},
+		{msgType: "sticker", content: webp, fileName: "sticker.webp", mediaType: "image/webp"},
+		{msgType: "sticker", content: png, fileName: "sticker.png", fails: true},
+		{msgType: "contact", content: png, fileName: "photo.png", fails: true},
+	}
+
+	for _, test := range tests {
+		stream := strings.NewReader(test.content)
+
+		mediaType, err := WAMediaSniff(test.msgType, stream, test.fileName)
+		if test.fails {
+			if err == nil {
+				t.Errorf("%s %s was accepted as %s", test.msgType, test.fileName, mediaType)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s %s answered %v", test.msgType, test.fileName, err)
+			continue
+		}
+
+		if !strings.HasPrefix(mediaType, test.mediaType) {
+			t.Errorf("%s %s is %s, want %s", test.msgType, test.fileName, mediaType, test.mediaType)
+		}
+
+		// The Stream is Rewound so it Can be Sent Whole
+		content, _ := ioutil.ReadAll(stream)
+		if string(content) != test.content {
+			t.Errorf("%s %s was not rewound", test.msgType, test.fileName)
+		}
+	}
+}
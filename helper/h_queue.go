@@ -0,0 +1,615 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Outbound Message Statuses
+const (
+	QueueScheduled = "scheduled"
+	QueueQueued    = "queued"
+	QueueSending   = "sending"
+	QueueSent      = "sent"
+	QueueDead      = "dead"
+	QueueCancelled = "cancelled"
+)
+
+// Outbound Queue Retry Policy
+var (
+	QueueMaxAttempts   = 5
+	QueueRetryDelay    = 5 * time.Second
+	QueueRetryDelayMax = 10 * time.Minute
+)
+
+// queueIdle is How Long a Worker Sleeps When it Has Nothing Due
+const queueIdle = time.Minute
+
+// Store Buckets Used by The Outbound Queue
+const (
+	bucketMessages   = "messages"
+	bucketOutbox     = "outbox"
+	bucketDeadLetter = "deadletter"
+)
+
+// QueueMessage Struct Describing an Outbound Message
+type QueueMessage struct {
+	ID                string      `json:"id"`
+	JID               string      `json:"jid"`
+	Type              string      `json:"type"`
+	To                string      `json:"to"`
+	Text              string      `json:"text,omitempty"`
+	MediaType         string      `json:"media_type,omitempty"`
+	FileName          string      `json:"file_name,omitempty"`
+	PTT               bool        `json:"ptt,omitempty"`
+	Latitude          float64     `json:"latitude,omitempty"`
+	Longitude         float64     `json:"longitude,omitempty"`
+	Name              string      `json:"name,omitempty"`
+	Address           string      `json:"address,omitempty"`
+	VCard             string      `json:"vcard,omitempty"`
+	QuotedID          string      `json:"quoted_message_id,omitempty"`
+	QuotedText        string      `json:"quoted_text,omitempty"`
+	QuotedParticipant string      `json:"quoted_participant,omitempty"`
+	SendAt            time.Time   `json:"send_at"`
+	Status            string      `json:"status"`
+	Attempts          int         `json:"attempts"`
+	Error             string      `json:"error,omitempty"`
+	ErrorCode         string      `json:"error_code,omitempty"`
+	Receipt           string      `json:"receipt,omitempty"`
+	DeliveredAt       *time.Time  `json:"delivered_at,omitempty"`
+	ReadAt            *time.Time  `json:"read_at,omitempty"`
+	Recipients        []Recipient `json:"recipients,omitempty"`
+	NextAttemptAt     time.Time   `json:"next_attempt_at"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+var queueWorkers = struct {
+	sync.Mutex
+	m map[string]chan struct{}
+}{m: make(map[string]chan struct{})}
+
+// WAQueueStart Function Wakes The Worker of Every Account With Pending Messages
+// and Keeps Waking Workers Whenever an Account Gets Connected
+func WAQueueStart() {
+	var jids []string
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		outbox := tx.Bucket([]byte(bucketOutbox))
+		if outbox == nil {
+			return nil
+		}
+
+		return outbox.ForEach(func(k, v []byte) error {
+			jids = append(jids, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		svc.Log("error", "queue", err.Error())
+	}
+
+	for _, jid := range jids {
+		queueWake(jid)
+	}
+
+	ch, _ := WASubscribe()
+	go func() {
+		for event := range ch {
+			if info, ok := event.Data.(SessionInfo); ok && event.Type == EventState && info.State == StateConnected {
+				queueWake(event.JID)
+			}
+		}
+	}()
+}
+
+// WAQueueMessage Function Queues a Message Without Media Described by msg
+func WAQueueMessage(jid string, msg QueueMessage) (QueueMessage, error) {
+	var err error
+
+	msg.To, err = waSendJid(msg.To)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.ID = newMessageID()
+
+	return queuePush(jid, msg)
+}
+
+// WAQueueMedia Function Queues a Media Message Described by msg
+// The Media and The Optional Thumbnail Are Kept in The Store Until Sent
+func WAQueueMedia(jid string, msg QueueMessage, msgMediaStream io.Reader, msgThumbnailStream io.Reader) (QueueMessage, error) {
+	var err error
+
+	msg.To, err = waSendJid(msg.To)
+	if err != nil {
+		return msg, err
+	}
+
+	msg.ID = newMessageID()
+
+	err = queueMediaSave(queueMediaFile(msg.ID), msgMediaStream)
+	if err != nil {
+		return msg, err
+	}
+
+	if msgThumbnailStream != nil {
+		err = queueMediaSave(queueThumbnailFile(msg.ID), msgThumbnailStream)
+		if err != nil {
+			queueMediaRemove(msg.ID)
+			return msg, err
+		}
+	}
+
+	return queuePush(jid, msg)
+}
+
+func WAQueueGet(jid string, id string) (QueueMessage, error) {
+	var msg QueueMessage
+	var found bool
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		var err error
+
+		msg, found, err = queueGet(tx, jid, id)
+		return err
+	})
+	if err != nil {
+		return msg, err
+	}
+
+	if !found {
+		return msg, fmt.Errorf("message %w", ErrNotFound)
+	}
+
+	return msg, nil
+}
+
+// WAQueueScheduled Function Returns Every Scheduled Message of a JID
+func WAQueueScheduled(jid string) ([]QueueMessage, error) {
+	msgs := make([]QueueMessage, 0)
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		outbox := storeBucketRead(tx, bucketOutbox, jid)
+		if outbox == nil {
+			return nil
+		}
+
+		return outbox.ForEach(func(k, v []byte) error {
+			msg, found, err := queueGet(tx, jid, string(v))
+			if err != nil {
+				return err
+			}
+
+			if found && msg.Status == QueueScheduled {
+				msgs = append(msgs, msg)
+			}
+
+			return nil
+		})
+	})
+
+	return msgs, err
+}
+
+// WAQueueCancel Function Cancels a Scheduled Message of a JID
+func WAQueueCancel(jid string, id string) (QueueMessage, error) {
+	var msg QueueMessage
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		var found bool
+		var err error
+
+		msg, found, err = queueGet(tx, jid, id)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			return fmt.Errorf("message %w", ErrNotFound)
+		}
+
+		if msg.Status == QueueSending {
+			return &waError{kind: ErrConflict, err: errors.New("message is being sent")}
+		}
+
+		if msg.Status != QueueScheduled {
+			return &waError{kind: ErrConflict, err: errors.New("message is not scheduled")}
+		}
+
+		outbox, err := storeBucket(tx, bucketOutbox, jid)
+		if err != nil {
+			return err
+		}
+
+		c := outbox.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if string(v) == id {
+				err = c.Delete()
+				if err != nil {
+					return err
+				}
+
+				break
+			}
+		}
+
+		msg.Status = QueueCancelled
+		msg.UpdatedAt = time.Now()
+
+		return queuePut(tx, msg)
+	})
+	if err != nil {
+		return msg, err
+	}
+
+	queueMediaRemove(msg.ID)
+
+	return msg, nil
+}
+
+func queuePush(jid string, msg QueueMessage) (QueueMessage, error) {
+	now := time.Now()
+
+	msg.JID = jid
+	msg.Status = QueueQueued
+	msg.Receipt = ReceiptPending
+	msg.NextAttemptAt = now
+	msg.CreatedAt = now
+	msg.UpdatedAt = now
+
+	if msg.SendAt.After(now) {
+		msg.Status = QueueScheduled
+		msg.NextAttemptAt = msg.SendAt
+	} else {
+		msg.SendAt = now
+	}
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		err := queuePut(tx, msg)
+		if err != nil {
+			return err
+		}
+
+		outbox, err := storeBucket(tx, bucketOutbox, jid)
+		if err != nil {
+			return err
+		}
+
+		sequence, err := outbox.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		return outbox.Put(storeSequenceKey(sequence), []byte(msg.ID))
+	})
+	if err != nil {
+		return msg, err
+	}
+
+	queueWake(jid)
+
+	return msg, nil
+}
+
+func queueGet(tx *bolt.Tx, jid string, id string) (QueueMessage, bool, error) {
+	var msg QueueMessage
+
+	bucket := storeBucketRead(tx, bucketMessages, jid)
+	if bucket == nil {
+		return msg, false, nil
+	}
+
+	data := bucket.Get([]byte(id))
+	if data == nil {
+		return msg, false, nil
+	}
+
+	return msg, true, json.Unmarshal(data, &msg)
+}
+
+func queuePut(tx *bolt.Tx, msg QueueMessage) error {
+	bucket, err := storeBucket(tx, bucketMessages, msg.JID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(msg.ID), data)
+}
+
+func queueMediaFile(id string) string {
+	return filepath.Join(svc.Config.GetString("SERVER_STORE_PATH"), bucketOutbox, id)
+}
+
+func queueThumbnailFile(id string) string {
+	return queueMediaFile(id) + ".thumb"
+}
+
+func queueMediaSave(file string, stream io.Reader) error {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+
+	buffer, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer buffer.Close()
+
+	_, err = io.Copy(buffer, stream)
+	return err
+}
+
+func queueMediaRemove(id string) {
+	_ = os.Remove(queueMediaFile(id))
+	_ = os.Remove(queueThumbnailFile(id))
+}
+
+// queueWake Function Signals The Worker of a JID, Starting it When Needed
+func queueWake(jid string) {
+	queueWorkers.Lock()
+	wake, ok := queueWorkers.m[jid]
+	if !ok {
+		wake = make(chan struct{}, 1)
+		queueWorkers.m[jid] = wake
+
+		go queueWork(jid, wake)
+	}
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	queueWorkers.Unlock()
+}
+
+// queueStop Function Stops The Worker of a JID
+func queueStop(jid string) {
+	queueWorkers.Lock()
+	if wake, ok := queueWorkers.m[jid]; ok {
+		delete(queueWorkers.m, jid)
+		close(wake)
+	}
+	queueWorkers.Unlock()
+}
+
+func queueWork(jid string, wake <-chan struct{}) {
+	for {
+		wait := queueProcess(jid)
+
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+		case <-time.After(wait):
+		}
+	}
+}
+
+// queueProcess Function Sends Every Due Message of a JID in Order
+// and Returns How Long to Wait Before Looking Again
+func queueProcess(jid string) time.Duration {
+	for {
+		if info, _ := wac.Info(jid); info.State != StateConnected {
+			return queueIdle
+		}
+
+		key, msg, wait, err := queueNext(jid)
+		if err != nil {
+			svc.Log("error", "queue", err.Error())
+			return QueueRetryDelay
+		}
+
+		if key == nil {
+			return wait
+		}
+
+		err = queueSend(jid, key, msg)
+		if err != nil {
+			svc.Log("error", "queue", err.Error())
+			return QueueRetryDelay
+		}
+	}
+}
+
+// queueNext Function Claims The First Due Message in The Outbox of a JID,
+// Marking it as Being Sent so it Can no Longer be Cancelled, or Returns
+// How Long Until The Next One is Due. Messages Left Being Sent by a
+// Previous Run Are Due Again
+func queueNext(jid string) ([]byte, QueueMessage, time.Duration, error) {
+	var key []byte
+	var msg QueueMessage
+
+	now := time.Now()
+	wait := queueIdle
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		outbox := storeBucketRead(tx, bucketOutbox, jid)
+		if outbox == nil {
+			return nil
+		}
+
+		c := outbox.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			pending, found, err := queueGet(tx, jid, string(v))
+			if err != nil {
+				return err
+			}
+
+			if !found {
+				continue
+			}
+
+			if !pending.NextAttemptAt.After(now) {
+				key = append([]byte(nil), k...)
+
+				pending.Status = QueueSending
+				pending.UpdatedAt = now
+				msg = pending
+
+				return queuePut(tx, msg)
+			}
+
+			if d := pending.NextAttemptAt.Sub(now); d < wait {
+				wait = d
+			}
+		}
+
+		return nil
+	})
+
+	return key, msg, wait, err
+}
+
+// queueSend Function Makes a Delivery Attempt and Records its Outcome
+func queueSend(jid string, key []byte, msg QueueMessage) error {
+	errSend := queueDeliver(jid, msg)
+
+	msg.Attempts++
+	msg.UpdatedAt = time.Now()
+
+	var receipt string
+	var receiptChanged bool
+
+	switch {
+	case errSend == nil:
+		msg.Status = QueueSent
+		msg.Error = ""
+		msg.ErrorCode = ""
+		receipt = ReceiptServerAck
+
+		svc.Log("info", "queue", "sent message "+msg.ID+" of "+jid)
+	case msg.Attempts >= QueueMaxAttempts, !waRetryable(errSend):
+		msg.Status = QueueDead
+		msg.Error = errSend.Error()
+		_, msg.ErrorCode = WAErrorCode(errSend)
+		receipt = ReceiptFailed
+
+		svc.Log("error", "queue", "dead-lettered message "+msg.ID+" of "+jid+" after "+strconv.Itoa(msg.Attempts)+" attempt(s): "+msg.Error)
+	default:
+		msg.Status = QueueQueued
+		msg.Error = errSend.Error()
+		_, msg.ErrorCode = WAErrorCode(errSend)
+		msg.NextAttemptAt = msg.UpdatedAt.Add(backoff(QueueRetryDelay, QueueRetryDelayMax, msg.Attempts-1))
+
+		svc.Log("warn", "queue", "failed to send message "+msg.ID+" of "+jid+": "+msg.Error)
+	}
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		// Acks Can Arrive Before The Send Returns, Keep Those Already Stored
+		stored, found, err := queueGet(tx, jid, msg.ID)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			msg.Receipt, msg.DeliveredAt, msg.ReadAt = stored.Receipt, stored.DeliveredAt, stored.ReadAt
+		}
+
+		if len(receipt) > 0 {
+			receiptChanged = receiptApply(&msg, receipt, msg.UpdatedAt)
+		}
+
+		err = queuePut(tx, msg)
+		if err != nil {
+			return err
+		}
+
+		if msg.Status == QueueQueued {
+			return nil
+		}
+
+		outbox, err := storeBucket(tx, bucketOutbox, jid)
+		if err != nil {
+			return err
+		}
+
+		err = outbox.Delete(key)
+		if err != nil {
+			return err
+		}
+
+		if msg.Status == QueueDead {
+			deadletter, err := storeBucket(tx, bucketDeadLetter, jid)
+			if err != nil {
+				return err
+			}
+
+			return deadletter.Put([]byte(msg.ID), []byte(msg.Type))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if msg.Status == QueueSent {
+		queueMediaRemove(msg.ID)
+		historySent(jid, msg)
+	}
+
+	if receiptChanged {
+		publishEvent(jid, EventStatus, MessageReceipt{ID: msg.ID, To: msg.To, Receipt: receipt, Time: msg.UpdatedAt})
+	}
+
+	return nil
+}
+
+func queueDeliver(jid string, msg QueueMessage) error {
+	context := waQuoteContext(msg.QuotedID, msg.QuotedText, msg.QuotedParticipant)
+
+	switch msg.Type {
+	case "text":
+		return WAMessageText(jid, msg.ID, msg.To, msg.Text, context)
+	case "location":
+		return WAMessageLocation(jid, msg.ID, msg.To, msg.Latitude, msg.Longitude, msg.Name, msg.Address, context)
+	case "contact":
+		return WAMessageContact(jid, msg.ID, msg.To, msg.Name, msg.VCard, context)
+	case "image", "document", "audio", "video", "sticker":
+		media, err := os.Open(queueMediaFile(msg.ID))
+		if err != nil {
+			return err
+		}
+		defer media.Close()
+
+		switch msg.Type {
+		case "image":
+			return WAMessageImage(jid, msg.ID, msg.To, media, msg.MediaType, msg.Text, context)
+		case "document":
+			return WAMessageDocument(jid, msg.ID, msg.To, media, msg.MediaType, msg.FileName, context)
+		case "audio":
+			return WAMessageAudio(jid, msg.ID, msg.To, media, msg.MediaType, msg.PTT, context)
+		case "video":
+			thumbnail, err := ioutil.ReadFile(queueThumbnailFile(msg.ID))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			return WAMessageVideo(jid, msg.ID, msg.To, media, msg.MediaType, msg.Text, thumbnail, context)
+		default:
+			return WAMessageSticker(jid, msg.ID, msg.To, media, msg.MediaType, context)
+		}
+	default:
+		return &waError{kind: ErrInvalidArgument, err: errors.New("unknown message type " + msg.Type)}
+	}
+}
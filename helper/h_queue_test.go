@@ -0,0 +1,142 @@
+package helper
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+)
+
+func TestQueueRetry(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	// The First Two Attempts Fail, The Third One Succeeds
+	var attempts int32
+	client.SendFunc = func(message interface{}) (string, error) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return "", errors.New("sending message timed out")
+		}
+
+		return "", nil
+	}
+
+	delay := QueueRetryDelay
+	QueueRetryDelay = 10 * time.Millisecond
+	defer func() { QueueRetryDelay = delay }()
+
+	jid := "retrying"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	msg, err := WAQueueMessage(jid, QueueMessage{Type: "text", To: "6281234567890", Text: "retried", SendAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEventually(t, func() bool {
+		msg, _ = WAQueueGet(jid, msg.ID)
+		return msg.Status == QueueSent
+	})
+
+	if msg.Attempts != 3 || len(msg.Error) > 0 {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
+
+func TestQueueDeadLetter(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	client.SendFunc = func(message interface{}) (string, error) {
+		return "", errors.New("sending message timed out")
+	}
+
+	delay := QueueRetryDelay
+	QueueRetryDelay = 10 * time.Millisecond
+	defer func() { QueueRetryDelay = delay }()
+
+	jid := "deadlettering"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	msg, err := WAQueueMessage(jid, QueueMessage{Type: "text", To: "6281234567890", Text: "never sent", SendAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEventually(t, func() bool {
+		msg, _ = WAQueueGet(jid, msg.ID)
+		return msg.Status == QueueDead
+	})
+
+	if msg.Attempts != QueueMaxAttempts || msg.Error != "sending message timed out" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
+
+func TestQueueCancelWhileSending(t *testing.T) {
+	client := wafake.NewClient()
+	NewClient = func(timeout int) (Client, error) {
+		return client, nil
+	}
+
+	sending := make(chan struct{})
+	release := make(chan struct{})
+
+	client.SendFunc = func(message interface{}) (string, error) {
+		close(sending)
+		<-release
+
+		return "", nil
+	}
+
+	jid := "cancelling"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	msg, err := WAQueueMessage(jid, QueueMessage{Type: "text", To: "6281234567890", Text: "later", SendAt: time.Now().Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Status != QueueScheduled {
+		t.Fatalf("message is %s, want %s", msg.Status, QueueScheduled)
+	}
+
+	select {
+	case <-sending:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not sent")
+	}
+
+	_, err = WAQueueCancel(jid, msg.ID)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("cancelling a message being sent returned %v, want %v", err, ErrConflict)
+	}
+
+	close(release)
+
+	testEventually(t, func() bool {
+		msg, _ = WAQueueGet(jid, msg.ID)
+		return msg.Status == QueueSent
+	})
+}
+
+func TestQueueBroadcastRecipient(t *testing.T) {
+	for _, to := range []string{"status@broadcast", "1562345678@broadcast"} {
+		_, err := WAQueueMessage("broadcaster", QueueMessage{Type: "text", To: to, Text: "to everyone"})
+		if !errors.Is(err, ErrInvalidRecipient) {
+			t.Errorf("queueing to %s returned %v, want %v", to, err, ErrInvalidRecipient)
+		}
+	}
+}
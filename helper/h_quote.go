@@ -0,0 +1,186 @@
+package helper
+
+import (
+	"errors"
+	"sync"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	bolt "go.etcd.io/bbolt"
+)
+
+// QuoteCacheSize is The Number of Recent Incoming Messages Kept per Account
+var QuoteCacheSize = 1000
+
+// quoteEntry Struct Describing a Message That Can be Quoted, The
+// Participant of Messages Sent by The Account Itself is Left Empty
+type quoteEntry struct {
+	Chat        string
+	Text        string
+	Participant string
+	FromMe      bool
+}
+
+// quoteCache Struct Keeping The Most Recent Messages of an Account
+type quoteCache struct {
+	entries map[string]quoteEntry
+	order   []string
+	next    int
+}
+
+var quotes = struct {
+	sync.Mutex
+	m map[string]*quoteCache
+}{m: make(map[string]*quoteCache)}
+
+// quoteRemember Function Caches an Incoming Message so Replies Can Quote it
+func quoteRemember(jid string, info whatsapp.MessageInfo, text string) {
+	if QuoteCacheSize < 1 || len(info.Id) == 0 {
+		return
+	}
+
+	participant := info.SenderJid
+	if len(participant) == 0 && !info.FromMe {
+		participant = info.RemoteJid
+	}
+
+	quotes.Lock()
+	defer quotes.Unlock()
+
+	cache, ok := quotes.m[jid]
+	if !ok {
+		cache = &quoteCache{entries: make(map[string]quoteEntry)}
+		quotes.m[jid] = cache
+	}
+
+	if _, ok := cache.entries[info.Id]; !ok {
+		// Evict The Oldest Entry Once The Ring is Full
+		if len(cache.order) < QuoteCacheSize {
+			cache.order = append(cache.order, info.Id)
+		} else {
+			delete(cache.entries, cache.order[cache.next])
+			cache.order[cache.next] = info.Id
+			cache.next = (cache.next + 1) % len(cache.order)
+		}
+	}
+
+	cache.entries[info.Id] = quoteEntry{
+		Chat:        waJidNormalize(info.RemoteJid),
+		Text:        text,
+		Participant: participant,
+		FromMe:      info.FromMe,
+	}
+}
+
+// quoteForget Function Drops The Cached Messages of a JID
+func quoteForget(jid string) {
+	quotes.Lock()
+	delete(quotes.m, jid)
+	quotes.Unlock()
+}
+
+// WAQuoteResolve Function Completes The Quoted Text and Participant of a Reply
+// to a Recipient From The Cache or The History, Values Given by The Caller
+// Take Precedence. Messages Known to Belong to Another Chat Are Rejected
+func WAQuoteResolve(jid string, to string, quotedID string, quotedText string, quotedParticipant string) (string, string, error) {
+	if len(quotedID) == 0 {
+		return "", "", nil
+	}
+
+	chat, err := waSendJid(to)
+	if err != nil {
+		return "", "", err
+	}
+
+	quotes.Lock()
+	entry, ok := quotes.m[jid].lookup(quotedID)
+	quotes.Unlock()
+
+	if !ok {
+		entry, ok, err = quoteHistory(jid, quotedID)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if ok && entry.Chat != chat {
+		return "", "", &waError{kind: ErrInvalidArgument, err: errors.New("quoted message belongs to another chat")}
+	}
+
+	if len(quotedText) == 0 {
+		quotedText = entry.Text
+	}
+
+	if len(quotedParticipant) == 0 {
+		quotedParticipant = entry.Participant
+	}
+
+	// Messages The Account Sent Are Attributed to The Account Itself
+	if len(quotedParticipant) == 0 && entry.FromMe {
+		quotedParticipant, err = waSelfJID(jid)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if !ok && len(quotedText) == 0 {
+		return "", "", &waError{kind: ErrInvalidArgument, err: errors.New("quoted message not found, provide quoted_text")}
+	}
+
+	return quotedText, quotedParticipant, nil
+}
+
+// quoteHistory Function Looks a Message Missing From The Cache up in The History
+func quoteHistory(jid string, id string) (quoteEntry, bool, error) {
+	var entry quoteEntry
+	var found bool
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		bucket, _, msg, err := historyLocate(tx, jid, id)
+		if err != nil || bucket == nil {
+			return err
+		}
+
+		found = true
+		entry = quoteEntry{
+			Chat:        msg.Chat,
+			Text:        msg.Text,
+			Participant: msg.Participant,
+			FromMe:      msg.Direction == HistoryOutbound,
+		}
+
+		if len(entry.Participant) == 0 && !entry.FromMe {
+			entry.Participant = msg.Chat
+		}
+
+		return nil
+	})
+
+	return entry, found, err
+}
+
+func (c *quoteCache) lookup(id string) (quoteEntry, bool) {
+	if c == nil {
+		return quoteEntry{}, false
+	}
+
+	entry, ok := c.entries[id]
+	return entry, ok
+}
+
+// waQuoteContext Function Builds The Context Info Threading a Reply
+func waQuoteContext(quotedID string, quotedText string, quotedParticipant string) whatsapp.ContextInfo {
+	if len(quotedID) == 0 {
+		return whatsapp.ContextInfo{}
+	}
+
+	return whatsapp.ContextInfo{
+		QuotedMessageID: quotedID,
+		QuotedMessage: &proto.Message{
+			Conversation: &quotedText,
+		},
+		Participant: quotedParticipant,
+	}
+}
@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestQuoteResolve(t *testing.T) {
+	jid := "quoting"
+
+	err := WASessionSave(WASessionFile(jid), whatsapp.Session{Wid: "6289999999999@c.us"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quoteRemember(jid, whatsapp.MessageInfo{Id: "QUOTE0001", RemoteJid: "6281111111111@s.whatsapp.net", FromMe: true}, "sent by us")
+	quoteRemember(jid, whatsapp.MessageInfo{Id: "QUOTE0002", RemoteJid: "6281111111111@s.whatsapp.net"}, "sent to us")
+
+	text, participant, err := WAQuoteResolve(jid, "6281111111111", "QUOTE0001", "", "")
+	if err != nil || text != "sent by us" || participant != "6289999999999@s.whatsapp.net" {
+		t.Errorf("own message resolved to %q, %q, %v", text, participant, err)
+	}
+
+	text, participant, err = WAQuoteResolve(jid, "6281111111111", "QUOTE0002", "", "")
+	if err != nil || text != "sent to us" || participant != "6281111111111@s.whatsapp.net" {
+		t.Errorf("received message resolved to %q, %q, %v", text, participant, err)
+	}
+
+	_, _, err = WAQuoteResolve(jid, "6282222222222", "QUOTE0002", "", "")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("quoting another chat returned %v, want %v", err, ErrInvalidArgument)
+	}
+
+	// Messages Missing From The Cache Are Looked up in The History
+	historyPut(jid, HistoryMessage{ID: "QUOTE0003", Chat: "6281111111111@s.whatsapp.net", Direction: HistoryInbound, Type: "text", Text: "kept in history", Timestamp: time.Now()})
+
+	_, _, err = WAQuoteResolve(jid, "6282222222222", "QUOTE0003", "", "")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("quoting another chat from history returned %v, want %v", err, ErrInvalidArgument)
+	}
+
+	text, participant, err = WAQuoteResolve(jid, "6281111111111", "QUOTE0003", "", "")
+	if err != nil || text != "kept in history" || participant != "6281111111111@s.whatsapp.net" {
+		t.Errorf("message from history resolved to %q, %q, %v", text, participant, err)
+	}
+}
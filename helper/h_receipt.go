@@ -0,0 +1,210 @@
+package helper
+
+import (
+	"encoding/json"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Message Receipts, in The Order a Sent Message Goes Through Them
+const (
+	ReceiptPending   = "pending"
+	ReceiptServerAck = "server_ack"
+	ReceiptDelivered = "delivered"
+	ReceiptRead      = "read"
+	ReceiptFailed    = "failed"
+)
+
+// receiptRank Orders Receipts so Acks Arriving Late Never Move a Message Back
+var receiptRank = map[string]int{
+	ReceiptPending:   1,
+	ReceiptServerAck: 2,
+	ReceiptDelivered: 3,
+	ReceiptRead:      4,
+	ReceiptFailed:    5,
+}
+
+// receiptAcks Maps The Ack Levels Sent by WhatsApp to Receipts
+var receiptAcks = map[int]string{
+	-1: ReceiptFailed,
+	0:  ReceiptPending,
+	1:  ReceiptServerAck,
+	2:  ReceiptDelivered,
+	3:  ReceiptRead,
+	4:  ReceiptRead,
+}
+
+// MessageReceipt Struct Describing a Receipt of a Sent Message
+type MessageReceipt struct {
+	ID          string    `json:"id"`
+	To          string    `json:"to"`
+	Receipt     string    `json:"receipt"`
+	Participant string    `json:"participant,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Recipient Struct Describing How Far a Message Sent to a Group Got With
+// One of its Participants
+type Recipient struct {
+	JID         string     `json:"jid"`
+	Receipt     string     `json:"receipt"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// receiptAck Struct Describing an Ack Sent by WhatsApp for One or More Messages
+type receiptAck struct {
+	Cmd         string          `json:"cmd"`
+	ID          json.RawMessage `json:"id"`
+	Ack         int             `json:"ack"`
+	To          string          `json:"to"`
+	Participant string          `json:"participant"`
+	T           int64           `json:"t"`
+}
+
+// receiptJSON Function Applies The Acks Among The JSON Messages WhatsApp Sends,
+// Ignoring Every Other JSON Message
+func receiptJSON(jid string, message string) {
+	var envelope []json.RawMessage
+
+	if json.Unmarshal([]byte(message), &envelope) != nil || len(envelope) < 2 {
+		return
+	}
+
+	var kind string
+
+	if json.Unmarshal(envelope[0], &kind) != nil || (kind != "Msg" && kind != "MsgInfo") {
+		return
+	}
+
+	var ack receiptAck
+
+	if json.Unmarshal(envelope[1], &ack) != nil || (ack.Cmd != "ack" && ack.Cmd != "acks") {
+		return
+	}
+
+	receipt, ok := receiptAcks[ack.Ack]
+	if !ok {
+		return
+	}
+
+	// A Single ID For "ack", a List of IDs For "acks"
+	var ids []string
+
+	if json.Unmarshal(ack.ID, &ids) != nil {
+		var id string
+
+		if json.Unmarshal(ack.ID, &id) != nil {
+			return
+		}
+
+		ids = []string{id}
+	}
+
+	at := time.Now()
+	if ack.T > 0 {
+		at = time.Unix(ack.T, 0)
+	}
+
+	for _, id := range ids {
+		receiptUpdate(jid, id, receipt, waJidNormalize(ack.Participant), at)
+	}
+}
+
+// receiptUpdate Function Records a Receipt of a Message Sent by a JID and
+// Publishes it as a Status Event, Unless The Message Already Got Further.
+// Acks of Group Participants Are Also Recorded per Participant
+func receiptUpdate(jid string, id string, receipt string, participant string, at time.Time) {
+	var msg QueueMessage
+	var changed bool
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		var found bool
+		var err error
+
+		msg, found, err = queueGet(tx, jid, id)
+		if err != nil || !found {
+			return err
+		}
+
+		changed = receiptApply(&msg, receipt, at)
+
+		if len(participant) > 0 && receiptApplyRecipient(&msg, participant, receipt, at) {
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+
+		return queuePut(tx, msg)
+	})
+	if err != nil {
+		svc.Log("error", "receipt", err.Error())
+		return
+	}
+
+	if changed {
+		publishEvent(jid, EventStatus, MessageReceipt{
+			ID:          msg.ID,
+			To:          msg.To,
+			Receipt:     receipt,
+			Participant: participant,
+			Time:        at,
+		})
+	}
+}
+
+// receiptApply Function Moves a Message Forward to a Receipt, Reporting
+// Whether it Did. In Groups This is How Far it Got With Any Participant
+func receiptApply(msg *QueueMessage, receipt string, at time.Time) bool {
+	if receiptRank[receipt] <= receiptRank[msg.Receipt] {
+		return false
+	}
+
+	msg.Receipt = receipt
+	receiptStamp(receipt, at, &msg.DeliveredAt, &msg.ReadAt)
+
+	return true
+}
+
+// receiptApplyRecipient Function Moves a Group Participant of a Message
+// Forward to a Receipt, Reporting Whether it Did
+func receiptApplyRecipient(msg *QueueMessage, participant string, receipt string, at time.Time) bool {
+	i := 0
+	for i < len(msg.Recipients) && msg.Recipients[i].JID != participant {
+		i++
+	}
+
+	if i == len(msg.Recipients) {
+		msg.Recipients = append(msg.Recipients, Recipient{JID: participant})
+	}
+
+	recipient := &msg.Recipients[i]
+
+	if receiptRank[receipt] <= receiptRank[recipient.Receipt] {
+		return false
+	}
+
+	recipient.Receipt = receipt
+	receiptStamp(receipt, at, &recipient.DeliveredAt, &recipient.ReadAt)
+
+	return true
+}
+
+// receiptStamp Function Sets The Times a Receipt Reached at Implies
+func receiptStamp(receipt string, at time.Time, deliveredAt **time.Time, readAt **time.Time) {
+	switch receipt {
+	case ReceiptDelivered:
+		*deliveredAt = &at
+	case ReceiptRead:
+		// Read Implies Delivered Even When That Ack Was Never Seen
+		if *deliveredAt == nil {
+			*deliveredAt = &at
+		}
+
+		*readAt = &at
+	}
+}
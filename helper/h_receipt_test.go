@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestReceiptGroupParticipants(t *testing.T) {
+	jid := "receipts"
+	now := time.Now()
+
+	msg := QueueMessage{
+		ID:        "3EB0RECEIPTS",
+		JID:       jid,
+		Type:      "text",
+		To:        "6281234567890-1562345678@g.us",
+		Status:    QueueSent,
+		Receipt:   ReceiptServerAck,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		return queuePut(tx, msg)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch, cancel := WASubscribe()
+	defer cancel()
+
+	receiptJSON(jid, `["Msg",{"cmd":"ack","id":"3EB0RECEIPTS","ack":3,"to":"6281234567890-1562345678@g.us","participant":"6281111111111@c.us"}]`)
+	receiptJSON(jid, `["Msg",{"cmd":"ack","id":"3EB0RECEIPTS","ack":2,"to":"6281234567890-1562345678@g.us","participant":"6282222222222@c.us"}]`)
+
+	// The Message Was Already Read by Someone, Yet The Second Participant
+	// Reading it Still Counts
+	receiptJSON(jid, `["Msg",{"cmd":"ack","id":"3EB0RECEIPTS","ack":3,"to":"6281234567890-1562345678@g.us","participant":"6282222222222@c.us"}]`)
+
+	msg, err = WAQueueGet(jid, msg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Receipt != ReceiptRead {
+		t.Fatalf("receipt is %s, want %s", msg.Receipt, ReceiptRead)
+	}
+
+	if len(msg.Recipients) != 2 {
+		t.Fatalf("got %d recipients, want 2: %+v", len(msg.Recipients), msg.Recipients)
+	}
+
+	for _, recipient := range msg.Recipients {
+		if recipient.Receipt != ReceiptRead || recipient.DeliveredAt == nil || recipient.ReadAt == nil {
+			t.Fatalf("unexpected recipient %+v", recipient)
+		}
+	}
+
+	statuses := 0
+	for statuses < 3 {
+		select {
+		case event := <-ch:
+			if event.Type == EventStatus && event.JID == jid {
+				statuses++
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("got %d status events, want 3", statuses)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package helper
+
+import (
+	"sync"
+	"time"
+)
+
+// State Type for WhatsApp Connection Lifecycle
+type State string
+
+// Connection Lifecycle States
+const (
+	StateInitializing State = "initializing"
+	StateAwaitingQR   State = "awaiting-qr"
+	StateConnected    State = "connected"
+	StateDisconnected State = "disconnected"
+	StateLoggedOut    State = "logged-out"
+)
+
+// SessionInfo Struct Describing a Connection in The Registry
+type SessionInfo struct {
+	JID       string    `json:"jid"`
+	State     State     `json:"state"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type registryEntry struct {
+	init      sync.Mutex
+	client    Client
+	state     State
+	updatedAt time.Time
+}
+
+// registry Struct Guarding The Connection of Every JID
+type registry struct {
+	mu      sync.RWMutex
+	entries map[string]*registryEntry
+}
+
+func newRegistry() *registry {
+	return &registry{
+		entries: make(map[string]*registryEntry),
+	}
+}
+
+// Get Method Returns The Client of a JID or Nil
+func (r *registry) Get(jid string) Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if e, ok := r.entries[jid]; ok {
+		return e.client
+	}
+
+	return nil
+}
+
+// Init Method Returns The Client of a JID, Creating it When Missing
+// Concurrent Calls for The Same JID Share a Single Client
+func (r *registry) Init(jid string, create func() (Client, error)) (Client, error) {
+	r.mu.Lock()
+	e, ok := r.entries[jid]
+	if !ok {
+		e = &registryEntry{}
+		r.entries[jid] = e
+	}
+	r.mu.Unlock()
+
+	e.init.Lock()
+	defer e.init.Unlock()
+
+	if client := r.Get(jid); client != nil {
+		return client, nil
+	}
+
+	r.SetState(jid, StateInitializing)
+
+	client, err := create()
+	if err != nil {
+		r.SetState(jid, StateDisconnected)
+		return nil, err
+	}
+
+	r.mu.Lock()
+	e.client = client
+	r.mu.Unlock()
+
+	return client, nil
+}
+
+// Remove Method Drops The Client of a JID and Records The Final State
+func (r *registry) Remove(jid string, state State) {
+	r.mu.Lock()
+	if e, ok := r.entries[jid]; ok {
+		e.client = nil
+	}
+	r.mu.Unlock()
+
+	r.SetState(jid, state)
+}
+
+// SetState Method Records The Lifecycle State of a JID
+// and Publishes a State Event When it Changes
+func (r *registry) SetState(jid string, state State) {
+	r.mu.Lock()
+	e, ok := r.entries[jid]
+	if !ok {
+		e = &registryEntry{}
+		r.entries[jid] = e
+	}
+
+	changed := e.state != state
+	e.state = state
+	e.updatedAt = time.Now()
+
+	info := SessionInfo{JID: jid, State: e.state, UpdatedAt: e.updatedAt}
+	r.mu.Unlock()
+
+	if changed {
+		publishEvent(jid, EventState, info)
+	}
+}
+
+// Info Method Returns The Session Information of a JID
+func (r *registry) Info(jid string) (SessionInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.entries[jid]
+	if !ok {
+		return SessionInfo{}, false
+	}
+
+	return SessionInfo{JID: jid, State: e.state, UpdatedAt: e.updatedAt}, true
+}
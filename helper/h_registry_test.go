@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+)
+
+func TestRegistryInitShared(t *testing.T) {
+	r := newRegistry()
+
+	var created int32
+	create := func() (Client, error) {
+		atomic.AddInt32(&created, 1)
+		return wafake.NewClient(), nil
+	}
+
+	var wg sync.WaitGroup
+	clients := make([]Client, 10)
+
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			client, err := r.Init("shared", create)
+			if err != nil {
+				t.Error(err)
+			}
+
+			clients[i] = client
+		}(i)
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Fatalf("created %d clients, want 1", created)
+	}
+
+	for _, client := range clients {
+		if client != clients[0] {
+			t.Fatal("concurrent calls got different clients")
+		}
+	}
+}
+
+func TestRegistryState(t *testing.T) {
+	r := newRegistry()
+
+	if _, ok := r.Info("state"); ok {
+		t.Fatal("unknown JID has a session")
+	}
+
+	_, err := r.Init("state", func() (Client, error) {
+		return nil, errors.New("no connection")
+	})
+	if err == nil {
+		t.Fatal("failed creation answered no error")
+	}
+
+	if info, _ := r.Info("state"); info.State != StateDisconnected || r.Get("state") != nil {
+		t.Fatalf("failed creation left %+v", info)
+	}
+
+	_, err = r.Init("state", func() (Client, error) {
+		return wafake.NewClient(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.SetState("state", StateConnected)
+	r.Remove("state", StateLoggedOut)
+
+	info, ok := r.Info("state")
+	if !ok || info.JID != "state" || info.State != StateLoggedOut || r.Get("state") != nil {
+		t.Fatalf("removed session is %+v", info)
+	}
+}
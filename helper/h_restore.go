@@ -0,0 +1,162 @@
+package helper
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+)
+
+// Session Restore Statuses
+const (
+	RestorePending  = "pending"
+	RestoreRestored = "restored"
+	RestoreFailed   = "failed"
+)
+
+// RestoreResult Struct Describing The Startup Restore of a Session
+type RestoreResult struct {
+	JID       string    `json:"jid"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RestoreSummary Struct Counting The Startup Restore Results by Status
+type RestoreSummary struct {
+	Pending  int `json:"pending"`
+	Restored int `json:"restored"`
+	Failed   int `json:"failed"`
+}
+
+var restoreResults = struct {
+	sync.RWMutex
+	m map[string]RestoreResult
+}{m: make(map[string]RestoreResult)}
+
+func setRestoreResult(jid string, status string, err error) {
+	result := RestoreResult{JID: jid, Status: status, UpdatedAt: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	restoreResults.Lock()
+	restoreResults.m[jid] = result
+	restoreResults.Unlock()
+}
+
+// restoreForget Function Drops The Restore Result of a JID
+func restoreForget(jid string) {
+	restoreResults.Lock()
+	delete(restoreResults.m, jid)
+	restoreResults.Unlock()
+}
+
+// WARestoreResults Function Returns The Startup Restore Result of Every Session
+func WARestoreResults() []RestoreResult {
+	restoreResults.RLock()
+	defer restoreResults.RUnlock()
+
+	results := make([]RestoreResult, 0, len(restoreResults.m))
+	for _, result := range restoreResults.m {
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].JID < results[j].JID
+	})
+
+	return results
+}
+
+// WARestoreSummary Function Counts The Startup Restore Results by Status
+// Without Revealing Which Sessions They Belong to
+func WARestoreSummary() RestoreSummary {
+	restoreResults.RLock()
+	defer restoreResults.RUnlock()
+
+	var summary RestoreSummary
+	for _, result := range restoreResults.m {
+		switch result.Status {
+		case RestorePending:
+			summary.Pending++
+		case RestoreRestored:
+			summary.Restored++
+		case RestoreFailed:
+			summary.Failed++
+		}
+	}
+
+	return summary
+}
+
+// WASessionRestoreAll Function Restores Every Session Stored in Path
+// Using at Most Concurrency Sessions in Parallel
+func WASessionRestoreAll(path string, timeout int, concurrency int) {
+	files, err := filepath.Glob(filepath.Join(path, "*.gob"))
+	if err != nil {
+		svc.Log("error", "session-restore", err.Error())
+		return
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for _, file := range files {
+		setRestoreResult(strings.TrimSuffix(filepath.Base(file), ".gob"), RestorePending, nil)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, file := range files {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(file string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			jid := strings.TrimSuffix(filepath.Base(file), ".gob")
+
+			err := waSessionRestoreFile(jid, file, timeout)
+			if err != nil {
+				svc.Log("error", "session-restore", "failed to restore session "+jid+": "+err.Error())
+				setRestoreResult(jid, RestoreFailed, err)
+				return
+			}
+
+			svc.Log("info", "session-restore", "restored session "+jid)
+			setRestoreResult(jid, RestoreRestored, nil)
+		}(file)
+	}
+
+	wg.Wait()
+}
+
+func waSessionRestoreFile(jid string, file string, timeout int) error {
+	session, err := WASessionLoad(file)
+	if err != nil {
+		return err
+	}
+
+	err = WAInit(jid, timeout)
+	if err != nil {
+		return err
+	}
+
+	err = WASessionRestore(jid, file, session)
+	if err != nil {
+		return err
+	}
+
+	waSupervise(jid, file, timeout)
+
+	return nil
+}
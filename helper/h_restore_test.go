@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestSessionRestoreAll(t *testing.T) {
+	dir, err := ioutil.TempDir(testDir, "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	NewClient = func(timeout int) (Client, error) {
+		return wafake.NewClient(), nil
+	}
+
+	err = WASessionSave(filepath.Join(dir, "restorable.gob"), whatsapp.Session{ClientId: "restorable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "corrupt.gob"), []byte("not a session"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	WASessionRestoreAll(dir, 5, 2)
+
+	results := WARestoreResults()
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+
+	if results[0].JID != "corrupt" || results[0].Status != RestoreFailed || len(results[0].Error) == 0 {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+
+	if results[1].JID != "restorable" || results[1].Status != RestoreRestored {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+
+	if summary := WARestoreSummary(); summary != (RestoreSummary{Restored: 1, Failed: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	if info, _ := WASessionInfo("restorable"); info.State != StateConnected {
+		t.Errorf("restored session is %s", info.State)
+	}
+}
@@ -0,0 +1,394 @@
+package helper
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wajid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// RosterPersist Enables Keeping Contact and Chat Lists in The Store
+// so They Are Known Before WhatsApp Sends Them Again After a Restart
+var RosterPersist = false
+
+// Store Bucket Keeping Contact and Chat Lists, Nested by JID and List
+const (
+	bucketRoster   = "roster"
+	rosterContacts = "contacts"
+	rosterChats    = "chats"
+)
+
+// Contact Struct Describing a Contact of an Account
+type Contact struct {
+	JID    string `json:"jid"`
+	Name   string `json:"name,omitempty"`
+	Notify string `json:"notify,omitempty"`
+	Short  string `json:"short,omitempty"`
+}
+
+// Chat Struct Describing a Chat of an Account
+type Chat struct {
+	JID           string    `json:"jid"`
+	Name          string    `json:"name,omitempty"`
+	IsGroup       bool      `json:"is_group"`
+	Unread        int       `json:"unread"`
+	IsMuted       bool      `json:"is_muted"`
+	IsSpam        bool      `json:"is_spam"`
+	LastMessageAt time.Time `json:"last_message_at"`
+}
+
+// roster Struct Keeping The Contacts and Chats of an Account
+type roster struct {
+	contacts map[string]Contact
+	chats    map[string]Chat
+}
+
+var rosters = struct {
+	sync.Mutex
+	m map[string]*roster
+}{m: make(map[string]*roster)}
+
+// WAContactList Function Returns The Contacts of a JID Sorted by Name
+func WAContactList(jid string) []Contact {
+	rosters.Lock()
+	r := rosterGet(jid)
+
+	contacts := make([]Contact, 0, len(r.contacts))
+	for _, contact := range r.contacts {
+		contacts = append(contacts, contact)
+	}
+	rosters.Unlock()
+
+	sort.Slice(contacts, func(i, j int) bool {
+		a, b := strings.ToLower(contactName(contacts[i])), strings.ToLower(contactName(contacts[j]))
+		if a != b {
+			return a < b
+		}
+
+		return contacts[i].JID < contacts[j].JID
+	})
+
+	return contacts
+}
+
+// WAChatList Function Returns The Chats of a JID, Most Recent First
+func WAChatList(jid string) []Chat {
+	rosters.Lock()
+	r := rosterGet(jid)
+
+	chats := make([]Chat, 0, len(r.chats))
+	for _, chat := range r.chats {
+		if len(chat.Name) == 0 {
+			chat.Name = contactName(r.contacts[chat.JID])
+		}
+
+		chats = append(chats, chat)
+	}
+	rosters.Unlock()
+
+	sort.Slice(chats, func(i, j int) bool {
+		if !chats[i].LastMessageAt.Equal(chats[j].LastMessageAt) {
+			return chats[i].LastMessageAt.After(chats[j].LastMessageAt)
+		}
+
+		return chats[i].JID < chats[j].JID
+	})
+
+	return chats
+}
+
+// rosterContactList Function Replaces The Contacts of a JID With The List
+// WhatsApp Sends After Login
+func rosterContactList(jid string, list []whatsapp.Contact) {
+	contacts := make(map[string]Contact, len(list))
+	for _, c := range list {
+		contact := rosterContact(c)
+		contacts[contact.JID] = contact
+	}
+
+	rosters.Lock()
+	rosterGet(jid).contacts = contacts
+	rosters.Unlock()
+
+	rosterSave(jid, rosterContacts, contacts, true)
+}
+
+// rosterContactPut Function Adds or Updates a Contact of a JID
+func rosterContactPut(jid string, c whatsapp.Contact) {
+	contact := rosterContact(c)
+
+	rosters.Lock()
+	rosterGet(jid).contacts[contact.JID] = contact
+	rosters.Unlock()
+
+	rosterSave(jid, rosterContacts, map[string]Contact{contact.JID: contact}, false)
+}
+
+// rosterChatList Function Replaces The Chats of a JID With The List
+// WhatsApp Sends After Login
+func rosterChatList(jid string, list []whatsapp.Chat) {
+	chats := make(map[string]Chat, len(list))
+	for _, c := range list {
+		chat := Chat{
+			JID:     waJidNormalize(c.Jid),
+			Name:    c.Name,
+			IsMuted: rosterFlag(c.IsMuted),
+			IsSpam:  rosterFlag(c.IsMarkedSpam),
+		}
+		chat.IsGroup = strings.HasSuffix(chat.JID, "@"+wajid.ServerGroup)
+		chat.Unread, _ = strconv.Atoi(c.Unread)
+
+		if seconds, err := strconv.ParseInt(c.LastMessageTime, 10, 64); err == nil && seconds > 0 {
+			chat.LastMessageAt = time.Unix(seconds, 0)
+		}
+
+		chats[chat.JID] = chat
+	}
+
+	rosters.Lock()
+	rosterGet(jid).chats = chats
+	rosters.Unlock()
+
+	rosterSave(jid, rosterChats, chats, true)
+}
+
+// rosterMessage Function Records a Message in The Chat it Belongs to,
+// Counting it as Unread Unless it Was Sent by The Account
+func rosterMessage(jid string, info whatsapp.MessageInfo) {
+	at := time.Unix(int64(info.Timestamp), 0)
+	if info.Timestamp == 0 {
+		at = time.Now()
+	}
+
+	rosterChatUpdate(jid, waJidNormalize(info.RemoteJid), func(chat *Chat) bool {
+		// Messages Replayed After a Reconnect Are Already Accounted For
+		if !at.After(chat.LastMessageAt) {
+			return false
+		}
+
+		chat.LastMessageAt = at
+
+		if info.FromMe {
+			chat.Unread = 0
+		} else {
+			chat.Unread++
+		}
+
+		return true
+	})
+}
+
+// rosterChatUpdate Function Changes a Chat of a JID, Creating it When
+// Missing, update Reports Whether it Changed Anything
+func rosterChatUpdate(jid string, chatJid string, update func(*Chat) bool) {
+	rosters.Lock()
+	r := rosterGet(jid)
+
+	chat, ok := r.chats[chatJid]
+	if !ok {
+		chat = Chat{JID: chatJid, IsGroup: strings.HasSuffix(chatJid, "@"+wajid.ServerGroup)}
+	}
+
+	changed := update(&chat)
+	if changed || !ok {
+		r.chats[chatJid] = chat
+	}
+	rosters.Unlock()
+
+	if changed || !ok {
+		rosterSave(jid, rosterChats, map[string]Chat{chatJid: chat}, false)
+	}
+}
+
+// rosterChatRemove Function Removes a Chat of a JID
+func rosterChatRemove(jid string, chatJid string) {
+	rosters.Lock()
+	delete(rosterGet(jid).chats, chatJid)
+	rosters.Unlock()
+
+	if !RosterPersist {
+		return
+	}
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		chats := storeBucketRead(tx, bucketRoster, jid, rosterChats)
+		if chats == nil {
+			return nil
+		}
+
+		return chats.Delete([]byte(chatJid))
+	})
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+	}
+}
+
+// rosterClear Function Forgets The Contacts and Chats of a JID
+func rosterClear(jid string) {
+	rosters.Lock()
+	delete(rosters.m, jid)
+	rosters.Unlock()
+
+	if !RosterPersist {
+		return
+	}
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketRoster)
+		if bucket == nil || bucket.Bucket([]byte(jid)) == nil {
+			return nil
+		}
+
+		return bucket.DeleteBucket([]byte(jid))
+	})
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+	}
+}
+
+// rosterGet Function Returns The Roster of a JID, Loading it From The
+// Store The First Time When Persisted, Must be Called Holding The Lock
+func rosterGet(jid string) *roster {
+	r, ok := rosters.m[jid]
+	if ok {
+		return r
+	}
+
+	r = &roster{contacts: make(map[string]Contact), chats: make(map[string]Chat)}
+	rosters.m[jid] = r
+
+	if !RosterPersist {
+		return r
+	}
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		err := rosterLoad(tx, jid, rosterContacts, func(v []byte) error {
+			var contact Contact
+
+			err := json.Unmarshal(v, &contact)
+			r.contacts[contact.JID] = contact
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		return rosterLoad(tx, jid, rosterChats, func(v []byte) error {
+			var chat Chat
+
+			err := json.Unmarshal(v, &chat)
+			r.chats[chat.JID] = chat
+
+			return err
+		})
+	})
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+	}
+
+	return r
+}
+
+// rosterLoad Function Reads Every Entry of a List of a JID
+func rosterLoad(tx *bolt.Tx, jid string, list string, read func([]byte) error) error {
+	bucket := storeBucketRead(tx, bucketRoster, jid, list)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.ForEach(func(k, v []byte) error {
+		return read(v)
+	})
+}
+
+// rosterSave Function Stores Entries of a List of a JID When Persisted,
+// Dropping The Stored List First When replace is Set
+func rosterSave(jid string, list string, entries interface{}, replace bool) {
+	if !RosterPersist {
+		return
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+		return
+	}
+
+	var values map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+		return
+	}
+
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		if replace {
+			if bucket := storeBucketRead(tx, bucketRoster, jid); bucket != nil && bucket.Bucket([]byte(list)) != nil {
+				err := bucket.DeleteBucket([]byte(list))
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		bucket, err := storeBucket(tx, bucketRoster, jid, list)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range values {
+			err = bucket.Put([]byte(key), value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		svc.Log("error", "roster", err.Error())
+	}
+}
+
+// rosterContact Function Converts a Contact Sent by WhatsApp
+func rosterContact(c whatsapp.Contact) Contact {
+	return Contact{
+		JID:    waJidNormalize(c.Jid),
+		Name:   c.Name,
+		Notify: c.Notify,
+		Short:  c.Short,
+	}
+}
+
+// rosterFlag Function Parses a Flag Sent by WhatsApp, Either a Boolean
+// or a Timestamp That is Set While The Flag is
+func rosterFlag(s string) bool {
+	if flag, err := strconv.ParseBool(s); err == nil {
+		return flag
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+
+	return err == nil && n != 0
+}
+
+// contactName Function Returns The Best Known Name of a Contact
+func contactName(contact Contact) string {
+	switch {
+	case len(contact.Name) > 0:
+		return contact.Name
+	case len(contact.Notify) > 0:
+		return contact.Notify
+	}
+
+	return contact.Short
+}
@@ -0,0 +1,62 @@
+package helper
+
+import (
+	"testing"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestRoster(t *testing.T) {
+	persist := RosterPersist
+	RosterPersist = true
+	defer func() { RosterPersist = persist }()
+
+	jid := "rostering"
+	handler := responseHandler{jid: jid}
+
+	handler.HandleContactList([]whatsapp.Contact{
+		{Jid: "6281111111111@c.us", Name: "Budi"},
+		{Jid: "6282222222222@c.us", Notify: "ani"},
+	})
+	handler.HandleChatList([]whatsapp.Chat{
+		{Jid: "6281111111111@c.us", Unread: "2", LastMessageTime: "1500000000"},
+		{Jid: "6280000000000-1500000000@g.us", Name: "Family", LastMessageTime: "1500000100"},
+	})
+	handler.HandleNewContact(whatsapp.Contact{Jid: "6283333333333@c.us", Name: "Citra"})
+
+	rosterMessage(jid, whatsapp.MessageInfo{RemoteJid: "6281111111111@s.whatsapp.net", Timestamp: 1500000200})
+	rosterMessage(jid, whatsapp.MessageInfo{RemoteJid: "6283333333333@s.whatsapp.net", Timestamp: 1500000300, FromMe: true})
+
+	// Drop The Cached Roster so The Lists Are Read Back From The Store
+	rosters.Lock()
+	delete(rosters.m, jid)
+	rosters.Unlock()
+
+	contacts := WAContactList(jid)
+	if len(contacts) != 3 || contacts[0].Notify != "ani" || contacts[1].Name != "Budi" || contacts[2].Name != "Citra" {
+		t.Fatalf("contacts are %+v", contacts)
+	}
+
+	chats := WAChatList(jid)
+	if len(chats) != 3 {
+		t.Fatalf("chats are %+v", chats)
+	}
+
+	if chats[0].JID != "6283333333333@s.whatsapp.net" || chats[0].Name != "Citra" || chats[0].Unread != 0 {
+		t.Errorf("most recent chat is %+v", chats[0])
+	}
+
+	if chats[1].JID != "6281111111111@s.whatsapp.net" || chats[1].Name != "Budi" || chats[1].Unread != 3 {
+		t.Errorf("second chat is %+v", chats[1])
+	}
+
+	if !chats[2].IsGroup || chats[2].Name != "Family" {
+		t.Errorf("group chat is %+v", chats[2])
+	}
+
+	rosterClear(jid)
+
+	if contacts := WAContactList(jid); len(contacts) != 0 {
+		t.Errorf("cleared roster still has contacts %+v", contacts)
+	}
+}
@@ -0,0 +1,262 @@
+package helper
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Search Term Length Bounds, Shorter Terms Match Too Much to be Indexed
+const (
+	searchTermMin = 2
+	searchTermMax = 64
+)
+
+// Store Buckets Keeping The Inverted Index of Message History, Nested by
+// JID and Term, Holding The History Keys of Matching Messages and Their Chat,
+// and How Many Messages Every Term of a JID Matches
+const (
+	bucketSearch       = "search"
+	bucketSearchCounts = "searchcounts"
+)
+
+// WASearch Function Returns a Page of The Messages of a JID Containing
+// Every Term of a Query, Most Recent First, Optionally Within a Chat
+func WASearch(jid string, q string, chatID string, query HistoryQuery) (HistoryPage, error) {
+	page := HistoryPage{Messages: make([]HistoryMessage, 0)}
+
+	terms := searchTerms(q)
+	if len(terms) == 0 {
+		return page, &waError{kind: ErrInvalidArgument, err: errors.New("query has no searchable terms")}
+	}
+
+	var chat string
+
+	if len(chatID) > 0 {
+		var err error
+
+		chat, err = waRemoteJid(chatID)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	seek, err := historySeek(&query)
+	if err != nil {
+		return page, err
+	}
+
+	err = svc.Store.View(func(tx *bolt.Tx) error {
+		counts := storeBucketRead(tx, bucketSearchCounts, jid)
+		buckets := make([]*bolt.Bucket, 0, len(terms))
+
+		for _, term := range terms {
+			bucket := storeBucketRead(tx, bucketSearch, jid, term)
+			if bucket == nil {
+				return nil
+			}
+
+			buckets = append(buckets, bucket)
+		}
+
+		// Walk The Rarest Term and Look The Others Up
+		driver := 0
+		for i, term := range terms {
+			if searchCount(counts, term) < searchCount(counts, terms[driver]) {
+				driver = i
+			}
+		}
+
+		return historyWalk(buckets[driver], seek, query, &page, func(k, v []byte) (*HistoryMessage, error) {
+			if len(chat) > 0 && string(v) != chat {
+				return nil, nil
+			}
+
+			for i, bucket := range buckets {
+				if i != driver && bucket.Get(k) == nil {
+					return nil, nil
+				}
+			}
+
+			history := storeBucketRead(tx, bucketHistory, jid, historyChats, string(v))
+			if history == nil {
+				return nil, nil
+			}
+
+			data := history.Get(k)
+			if data == nil {
+				return nil, nil
+			}
+
+			var msg HistoryMessage
+
+			return &msg, json.Unmarshal(data, &msg)
+		})
+	})
+
+	return page, err
+}
+
+// WASearchReindex Function Builds The Index of Every JID Whose History Was
+// Stored Before it Was Indexed or Before Terms Were Counted. Must Run Before
+// Any Message is Stored so it Does Not Race With historyPut
+func WASearchReindex() {
+	var jids []string
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket([]byte(bucketHistory))
+		if history == nil {
+			return nil
+		}
+
+		return history.ForEach(func(k, v []byte) error {
+			if v == nil && storeBucketRead(tx, bucketSearchCounts, string(k)) == nil {
+				jids = append(jids, string(k))
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		svc.Log("error", "search", err.Error())
+		return
+	}
+
+	for _, jid := range jids {
+		count, err := searchReindex(jid)
+		if err != nil {
+			svc.Log("error", "search", "failed to index history of "+jid+": "+err.Error())
+			continue
+		}
+
+		svc.Log("info", "search", fmt.Sprintf("indexed %d message(s) of %v", count, jid))
+	}
+}
+
+// searchReindex Function Rebuilds The Index of a JID From its History in a
+// Single Transaction, so The Term Counts Marking it as Done Are Only Stored
+// Along With a Complete Index
+func searchReindex(jid string) (int, error) {
+	count := 0
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		if search := tx.Bucket([]byte(bucketSearch)); search != nil && search.Bucket([]byte(jid)) != nil {
+			err := search.DeleteBucket([]byte(jid))
+			if err != nil {
+				return err
+			}
+		}
+
+		// Create The Counts Even When There is Nothing to Index
+		_, err := storeBucket(tx, bucketSearchCounts, jid)
+		if err != nil {
+			return err
+		}
+
+		chats := storeBucketRead(tx, bucketHistory, jid, historyChats)
+		if chats == nil {
+			return nil
+		}
+
+		return chats.ForEach(func(chat, v []byte) error {
+			bucket := chats.Bucket(chat)
+			if v != nil || bucket == nil {
+				return nil
+			}
+
+			return bucket.ForEach(func(k, v []byte) error {
+				var msg HistoryMessage
+
+				err := json.Unmarshal(v, &msg)
+				if err != nil {
+					return err
+				}
+
+				count++
+
+				return searchIndex(tx, jid, msg, append([]byte(nil), k...))
+			})
+		})
+	})
+
+	return count, err
+}
+
+// searchIndex Function Adds a Message Stored in History Under a Key to
+// The Index of a JID, Counting Every Term Once per Message
+func searchIndex(tx *bolt.Tx, jid string, msg HistoryMessage, key []byte) error {
+	counts, err := storeBucket(tx, bucketSearchCounts, jid)
+	if err != nil {
+		return err
+	}
+
+	for _, term := range searchTerms(msg.Text + " " + msg.FileName) {
+		bucket, err := storeBucket(tx, bucketSearch, jid, term)
+		if err != nil {
+			return err
+		}
+
+		// Already Indexed, as When The Media of a Message is Recorded Later
+		if bucket.Get(key) != nil {
+			continue
+		}
+
+		err = bucket.Put(key, []byte(msg.Chat))
+		if err != nil {
+			return err
+		}
+
+		count := make([]byte, 8)
+		binary.BigEndian.PutUint64(count, searchCount(counts, term)+1)
+
+		err = counts.Put([]byte(term), count)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// searchCount Function Returns How Many Messages a Term Matches
+func searchCount(counts *bolt.Bucket, term string) uint64 {
+	if counts == nil {
+		return 0
+	}
+
+	count := counts.Get([]byte(term))
+	if len(count) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(count)
+}
+
+// searchTerms Function Splits a Text Into The Distinct Lower Case Words
+// and Numbers it is Indexed or Searched By
+func searchTerms(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) < searchTermMin || len(field) > searchTermMax || seen[field] {
+			continue
+		}
+
+		seen[field] = true
+		terms = append(terms, field)
+	}
+
+	return terms
+}
@@ -0,0 +1,119 @@
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	bolt "go.etcd.io/bbolt"
+)
+
+// testSearch Function Returns The IDs of The Messages of a JID Matching q
+func testSearch(t *testing.T, jid string, q string) []string {
+	t.Helper()
+
+	page, err := WASearch(jid, q, "", HistoryQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, 0, len(page.Messages))
+	for _, msg := range page.Messages {
+		ids = append(ids, msg.ID)
+	}
+
+	return ids
+}
+
+func TestSearchReindex(t *testing.T) {
+	jid := "reindexing"
+
+	// Store History The Way it Was Stored Before it Was Indexed
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		for i, text := range []string{"invoice for march", "see you tomorrow", "march invoice paid"} {
+			msg := HistoryMessage{
+				ID:        "OLD" + string(rune('A'+i)),
+				Chat:      "6281234567890@s.whatsapp.net",
+				Direction: HistoryInbound,
+				Type:      "text",
+				Text:      text,
+				Timestamp: time.Unix(int64(1600000000+i), 0),
+			}
+
+			chat, err := storeBucket(tx, bucketHistory, jid, historyChats, msg.Chat)
+			if err != nil {
+				return err
+			}
+
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+
+			err = chat.Put(historyCursorKey(msg), data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := testSearch(t, jid, "invoice"); len(ids) != 0 {
+		t.Fatalf("history is indexed already: %v", ids)
+	}
+
+	WASearchReindex()
+
+	ids := testSearch(t, jid, "march invoice")
+	if len(ids) != 2 || ids[0] != "OLDC" || ids[1] != "OLDA" {
+		t.Fatalf("unexpected matches %v", ids)
+	}
+
+	// Indexed JIDs Are Left Alone, so Terms Are Not Counted Twice
+	WASearchReindex()
+
+	err = svc.Store.View(func(tx *bolt.Tx) error {
+		if count := searchCount(storeBucketRead(tx, bucketSearchCounts, jid), "invoice"); count != 2 {
+			t.Errorf("invoice is counted %d time(s), want 2", count)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchMediaFileName(t *testing.T) {
+	jid := "searchmedia"
+
+	info := whatsapp.MessageInfo{Id: "DOC1", RemoteJid: "6281234567890@s.whatsapp.net", Timestamp: 1600000000}
+
+	historyReceived(jid, "document", info, "")
+	historyMedia(jid, info.Id, "/tmp/DOC1.pdf", "application/pdf", "Quarterly-Report.pdf")
+
+	if ids := testSearch(t, jid, "quarterly report"); len(ids) != 1 || ids[0] != info.Id {
+		t.Fatalf("unexpected matches %v", ids)
+	}
+
+	// Recording The Media Again Does Not Count The Terms Again
+	historyMedia(jid, info.Id, "/tmp/DOC1.pdf", "application/pdf", "Quarterly-Report.pdf")
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		if count := searchCount(storeBucketRead(tx, bucketSearchCounts, jid), "quarterly"); count != 1 {
+			t.Errorf("quarterly is counted %d time(s), want 1", count)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	"github.com/spf13/viper"
+	bolt "go.etcd.io/bbolt"
+)
+
+// testDir is The Temporary Directory Holding The Store of The Tests
+var testDir string
+
+// TestMain Function Opens a Temporary Store For The Tests
+func TestMain(m *testing.M) {
+	var err error
+
+	testDir, err = ioutil.TempDir("", "go-whatsapp-rest")
+	if err != nil {
+		panic(err)
+	}
+
+	svc.Config = viper.New()
+	svc.Config.Set("SERVER_STORE_PATH", testDir)
+	svc.Config.Set("SERVER_UPLOAD_PATH", filepath.Join(testDir, "uploads"))
+
+	svc.Store, err = bolt.Open(filepath.Join(testDir, "whatsapp.db"), 0600, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	svc.Store.Close()
+	os.RemoveAll(testDir)
+
+	os.Exit(code)
+}
+
+// testLogin Function Logs a JID in Through The Client Returned by NewClient
+func testLogin(t *testing.T, jid string) {
+	t.Helper()
+
+	login := WALoginStart(jid, filepath.Join(testDir, jid+".gob"), 5)
+	testEventually(t, func() bool {
+		login, _ = WALoginGet(jid, login.ID)
+		return login.Status == LoginSuccess
+	})
+}
+
+// testEventually Function Waits For a Condition to Hold
+func testEventually(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
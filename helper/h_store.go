@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// storeBucket Function Returns a Nested Bucket, Creating it When Missing
+// Must be Called Inside a Writable Transaction
+func storeBucket(tx *bolt.Tx, names ...string) (*bolt.Bucket, error) {
+	bucket, err := tx.CreateBucketIfNotExists([]byte(names[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names[1:] {
+		bucket, err = bucket.CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bucket, nil
+}
+
+// storeBucketRead Function Returns a Nested Bucket or Nil When Missing
+func storeBucketRead(tx *bolt.Tx, names ...string) *bolt.Bucket {
+	bucket := tx.Bucket([]byte(names[0]))
+
+	for _, name := range names[1:] {
+		if bucket == nil {
+			return nil
+		}
+
+		bucket = bucket.Bucket([]byte(name))
+	}
+
+	return bucket
+}
+
+// storeSequenceKey Function Encodes a Bucket Sequence as a Sortable Key
+func storeSequenceKey(sequence uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, sequence)
+
+	return key
+}
+
+// newMessageID Function Generates an ID in The Format Used by WhatsApp Web
+func newMessageID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return "3EB0" + strings.ToUpper(hex.EncodeToString(id))
+}
@@ -0,0 +1,195 @@
+package helper
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+// Reconnect Backoff Bounds
+var (
+	ReconnectDelayMin = 1 * time.Second
+	ReconnectDelayMax = 5 * time.Minute
+)
+
+// supervisor Struct Reconnecting a Single Account When its Connection Drops
+type supervisor struct {
+	jid     string
+	file    string
+	timeout int
+	notify  chan Client
+	stop    chan struct{}
+}
+
+var supervisors = struct {
+	sync.Mutex
+	m map[string]*supervisor
+}{m: make(map[string]*supervisor)}
+
+// waSupervise Function Starts The Supervisor of a JID When Not Running
+func waSupervise(jid string, file string, timeout int) {
+	supervisors.Lock()
+	defer supervisors.Unlock()
+
+	if _, ok := supervisors.m[jid]; ok {
+		return
+	}
+
+	s := &supervisor{
+		jid:     jid,
+		file:    file,
+		timeout: timeout,
+		notify:  make(chan Client, 1),
+		stop:    make(chan struct{}),
+	}
+	supervisors.m[jid] = s
+
+	go s.run()
+}
+
+// waUnsupervise Function Stops The Supervisor of a JID
+func waUnsupervise(jid string) {
+	supervisors.Lock()
+	defer supervisors.Unlock()
+
+	if s, ok := supervisors.m[jid]; ok {
+		close(s.stop)
+		delete(supervisors.m, jid)
+	}
+}
+
+// waDisconnected Function Reports a Dropped Client to its Supervisor
+func waDisconnected(jid string, conn Client, err error) {
+	svc.Log("warn", "supervisor", "connection of "+jid+" dropped: "+err.Error())
+
+	supervisors.Lock()
+	s, ok := supervisors.m[jid]
+	supervisors.Unlock()
+
+	if !ok {
+		// Ignore Reports About a Client That Was Already Replaced
+		if wac.Get(jid) == conn {
+			wac.Remove(jid, StateDisconnected)
+		}
+		return
+	}
+
+	select {
+	case s.notify <- conn:
+	default:
+	}
+}
+
+// isDisconnectError Function Reports Whether an Error Means The Websocket is Gone
+func isDisconnectError(err error) bool {
+	switch err.(type) {
+	case *whatsapp.ErrConnectionFailed, *whatsapp.ErrConnectionClosed:
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "websocket: close sent")
+}
+
+func (s *supervisor) run() {
+	for {
+		select {
+		case <-s.stop:
+			return
+		case conn := <-s.notify:
+			// Ignore Reports About a Client That Was Already Replaced
+			if current := wac.Get(s.jid); current != nil && current != conn {
+				continue
+			}
+
+			s.reconnect()
+		}
+	}
+}
+
+func (s *supervisor) reconnect() {
+	for attempt := 0; ; attempt++ {
+		// Close The Dropped or Failed Client so its Websocket Does Not Leak
+		if conn := wac.Get(s.jid); conn != nil {
+			conn.RemoveHandlers()
+			_ = conn.Disconnect()
+		}
+
+		wac.Remove(s.jid, StateDisconnected)
+
+		if _, err := os.Stat(s.file); os.IsNotExist(err) {
+			svc.Log("warn", "supervisor", "session of "+s.jid+" is gone, giving up reconnecting")
+			wac.SetState(s.jid, StateLoggedOut)
+			return
+		}
+
+		err := waSessionReconnect(s.jid, s.file, s.timeout)
+		if err == nil {
+			svc.Log("info", "supervisor", "reconnected "+s.jid+" after "+strconv.Itoa(attempt+1)+" attempt(s)")
+			return
+		}
+
+		delay := backoff(ReconnectDelayMin, ReconnectDelayMax, attempt)
+		svc.Log("warn", "supervisor", "failed to reconnect "+s.jid+": "+err.Error()+", retrying in "+delay.String())
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// waSessionReconnect Function Restores a Stored Session on a New Client
+// Unlike WASessionRestore it Never Logs Out on Failure
+func waSessionReconnect(jid string, file string, timeout int) error {
+	session, err := WASessionLoad(file)
+	if err != nil {
+		return err
+	}
+
+	err = WAInit(jid, timeout)
+	if err != nil {
+		return err
+	}
+
+	conn := wac.Get(jid)
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	session, err = conn.RestoreWithSession(session)
+	if err != nil && !errors.Is(err, whatsapp.ErrAlreadyLoggedIn) {
+		return waErrorWrap(err)
+	}
+
+	wac.SetState(jid, StateConnected)
+
+	if err == nil {
+		return WASessionSave(file, session)
+	}
+
+	return nil
+}
+
+// backoff Function Returns The Jittered Exponential Delay of an Attempt
+func backoff(min time.Duration, max time.Duration, attempt int) time.Duration {
+	delay := max
+	if attempt < 32 {
+		if d := min << uint(attempt); d > 0 && d < max {
+			delay = d
+		}
+	}
+
+	// Equal Jitter, Keep Half of The Delay and Randomize The Rest
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/theveloped/go-whatsapp-rest/wafake"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+func TestSupervisorReconnect(t *testing.T) {
+	var mu sync.Mutex
+	var clients []*wafake.Client
+
+	// The First Reconnect Attempt Fails, The Second One Succeeds
+	NewClient = func(timeout int) (Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		client := wafake.NewClient()
+		if len(clients) == 1 {
+			client.RestoreFunc = func(session whatsapp.Session) (whatsapp.Session, error) {
+				return whatsapp.Session{}, errors.New("restore failed")
+			}
+		}
+
+		clients = append(clients, client)
+
+		return client, nil
+	}
+
+	ReconnectDelayMin = 10 * time.Millisecond
+
+	jid := "supervised"
+	defer waUnsupervise(jid)
+
+	testLogin(t, jid)
+
+	mu.Lock()
+	dropped := clients[0]
+	mu.Unlock()
+
+	dropped.Drop()
+
+	testEventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		info, _ := WASessionInfo(jid)
+		return len(clients) == 3 && info.State == StateConnected
+	})
+
+	if dropped.Disconnects() != 1 {
+		t.Errorf("dropped client disconnected %d times, want 1", dropped.Disconnects())
+	}
+
+	if clients[1].Disconnects() != 1 {
+		t.Errorf("failed client disconnected %d times, want 1", clients[1].Disconnects())
+	}
+
+	err := WAMessageText(jid, "", "6281234567890", "reconnected", whatsapp.ContextInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clients[2].Sent()) != 1 {
+		t.Errorf("reconnected client sent %d messages, want 1", len(clients[2].Sent()))
+	}
+}
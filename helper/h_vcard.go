@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"strings"
+)
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// WAVCard Function Builds a vCard 3.0 Contact Card
+// The Phone Number is Tagged With its WhatsApp ID so The Card Links to The Chat
+func WAVCard(name string, phone string, organization string, email string) string {
+	var card strings.Builder
+
+	card.WriteString("BEGIN:VCARD\n")
+	card.WriteString("VERSION:3.0\n")
+	card.WriteString("N:;" + vcardEscaper.Replace(name) + ";;;\n")
+	card.WriteString("FN:" + vcardEscaper.Replace(name) + "\n")
+
+	if len(organization) > 0 {
+		card.WriteString("ORG:" + vcardEscaper.Replace(organization) + ";\n")
+	}
+
+	if len(phone) > 0 {
+		waid := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, phone)
+
+		card.WriteString("TEL;type=CELL;type=VOICE;waid=" + waid + ":" + vcardEscaper.Replace(phone) + "\n")
+	}
+
+	if len(email) > 0 {
+		card.WriteString("EMAIL;type=INTERNET:" + vcardEscaper.Replace(email) + "\n")
+	}
+
+	card.WriteString("END:VCARD")
+
+	return card.String()
+}
+
+// WAVCardName Function Returns The Formatted Name of a vCard
+func WAVCardName(vcard string) string {
+	for _, line := range strings.Split(strings.Replace(vcard, "\r\n", "\n", -1), "\n") {
+		if strings.HasPrefix(strings.ToUpper(line), "FN:") || strings.HasPrefix(strings.ToUpper(line), "FN;") {
+			if i := strings.Index(line, ":"); i >= 0 {
+				return strings.TrimSpace(line[i+1:])
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,26 @@
+package helper
+
+import (
+	"testing"
+)
+
+func TestVCard(t *testing.T) {
+	card := WAVCard("Budi; Santoso", "+62 812-0000-0001", "Acme, Inc.", "budi@example.com")
+
+	want := "BEGIN:VCARD\n" +
+		"VERSION:3.0\n" +
+		"N:;Budi\\; Santoso;;;\n" +
+		"FN:Budi\\; Santoso\n" +
+		"ORG:Acme\\, Inc.;\n" +
+		"TEL;type=CELL;type=VOICE;waid=6281200000001:+62 812-0000-0001\n" +
+		"EMAIL;type=INTERNET:budi@example.com\n" +
+		"END:VCARD"
+
+	if card != want {
+		t.Fatalf("unexpected card\n%s\nwant\n%s", card, want)
+	}
+
+	if name := WAVCardName("BEGIN:VCARD\r\nVERSION:3.0\r\nFN;CHARSET=UTF-8:Siti\r\nEND:VCARD"); name != "Siti" {
+		t.Errorf("name is %q, want Siti", name)
+	}
+}
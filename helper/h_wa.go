@@ -1,112 +1,173 @@
 package helper
 
 import (
-	"encoding/base64"
 	"encoding/gob"
 	"errors"
-	"mime/multipart"
+	"io"
 	"os"
-	"strings"
 	"time"
 
 	"fmt"
 
-	"bytes"
-	"net/http"
-	"encoding/json"
-
 	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wajid"
+
 	whatsapp "github.com/Rhymen/go-whatsapp"
-	qrcode "github.com/skip2/go-qrcode"
 )
 
-type responseHandler struct{
-	webhook 	string
-    created 	uint64
+type responseHandler struct {
+	jid    string
+	client Client
 }
 
-type messageTextResponse struct {
-    whatsapp.TextMessage
-    Response DialogResponse
+func (wh responseHandler) HandleError(err error) {
+	fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+
+	if isDisconnectError(err) {
+		waDisconnected(wh.jid, wh.client, err)
+	}
 }
 
-type messageImageResponse struct {
-    whatsapp.ImageMessage
-    Response DialogResponse
+// received Method Records an Incoming or Outgoing Message Before it is Handled
+func (wh responseHandler) received(msgType string, info whatsapp.MessageInfo, text string) {
+	quoteRemember(wh.jid, info, text)
+	rosterMessage(wh.jid, info)
+	historyReceived(wh.jid, msgType, info, text)
 }
 
-func (wh responseHandler) HandleError(err error) {
-	fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+// accepts Method Reports Whether an Incoming Message Should be Published
+func (wh responseHandler) accepts(info whatsapp.MessageInfo) bool {
+	return !info.FromMe
+}
+
+// intent Method Runs The Dialogflow Intent Detection for an Incoming Message
+func (wh responseHandler) intent(info whatsapp.MessageInfo, text string) *DialogResponse {
+	remoteJid := wajid.User(info.RemoteJid)
+	dialogResponse, err := DetectIntentText(svc.Config.GetString("DIALOGFLOW_PROJECT_ID"), remoteJid, text, "en")
+
+	if err != nil {
+		fmt.Printf("[!] %v\n", err)
+	}
+
+	return &dialogResponse
 }
 
 func (wh responseHandler) HandleTextMessage(message whatsapp.TextMessage) {
-	if !message.Info.FromMe {
+	wh.received("text", message.Info, message.Text)
+
+	if wh.accepts(message.Info) {
 		fmt.Printf("[+] Handling text message\n")
 
 		var textArray [256]byte
 		copy(textArray[:], message.Text)
 		limitedText := string(textArray[:])
 
-		remoteJid := strings.Split(message.Info.RemoteJid, "@")[0]
-		dialogResponse, err := DetectIntentText(svc.Config.GetString("DIALOGFLOW_PROJECT_ID"), remoteJid, limitedText, "en")
-
-		if err != nil {
-			fmt.Printf("[!] %v\n", err)
+		envelope := waWebhookMessage(wh.jid, "text", message.Info, message, nil, "", "")
+		if waWebhookWants(wh.jid, EventMessage+".text") {
+			envelope.Response = wh.intent(message.Info, limitedText)
 		}
 
-		responseMessage := messageTextResponse{TextMessage: message, Response: dialogResponse}
-
-		jsonStr, _ := json.Marshal(responseMessage)
-		_, _ = http.Post(wh.webhook, "application/json", bytes.NewBuffer(jsonStr))
+		publishEvent(wh.jid, EventMessage, envelope)
 	}
 }
 
 func (wh responseHandler) HandleImageMessage(message whatsapp.ImageMessage) {
-	if !message.Info.FromMe {
-		fmt.Printf("[+] Handling image message\n")
+	wh.received("image", message.Info, message.Caption)
 
-		imageIntent := fmt.Sprintf("image: %v", message.Info.Id)
-		remoteJid := strings.Split(message.Info.RemoteJid, "@")[0]
-		dialogResponse, err := DetectIntentText(svc.Config.GetString("DIALOGFLOW_PROJECT_ID"), remoteJid, imageIntent, "en")
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling image message\n")
 
-		if err != nil {
-			fmt.Printf("[!] %v\n", err)
+		envelope := waWebhookMessage(wh.jid, "image", message.Info, message, &message, message.Type, "")
+		if waWebhookWants(wh.jid, EventMessage+".image") {
+			envelope.Response = wh.intent(message.Info, fmt.Sprintf("image: %v", message.Info.Id))
 		}
 
-		responseMessage := messageImageResponse{ImageMessage: message, Response: dialogResponse}
+		publishEvent(wh.jid, EventMessage, envelope)
+	}
+}
 
-		jsonStr, _ := json.Marshal(responseMessage)
-		_, _ = http.Post(wh.webhook, "application/json", bytes.NewBuffer(jsonStr))
+func (wh responseHandler) HandleVideoMessage(message whatsapp.VideoMessage) {
+	wh.received("video", message.Info, message.Caption)
 
-		data, err := message.Download()
-		if err != nil {
-			fmt.Printf("[!] %v\n", err)
-			return
-		}
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling video message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "video", message.Info, message, &message, message.Type, ""))
+	}
+}
 
-		filename := fmt.Sprintf("%v/%v.%v", svc.Config.GetString("SERVER_UPLOAD_PATH"), message.Info.Id, strings.Split(message.Type, "/")[1])
-		file, err := os.Create(filename)
-		defer file.Close()
-		if err != nil {
-			fmt.Printf("[!] %v\n", err)
-			return
-		}
+func (wh responseHandler) HandleAudioMessage(message whatsapp.AudioMessage) {
+	wh.received("audio", message.Info, "")
 
-		_, err = file.Write(data)
-		if err != nil {
-			fmt.Printf("[!] %v\n", err)
-			return
-		}
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling audio message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "audio", message.Info, message, &message, message.Type, ""))
+	}
+}
+
+func (wh responseHandler) HandleDocumentMessage(message whatsapp.DocumentMessage) {
+	wh.received("document", message.Info, message.Title)
+
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling document message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "document", message.Info, message, &message, message.Type, message.FileName))
+	}
+}
+
+func (wh responseHandler) HandleStickerMessage(message whatsapp.StickerMessage) {
+	wh.received("sticker", message.Info, "")
 
-		fmt.Printf("[!] stored: %v\n", filename)
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling sticker message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "sticker", message.Info, message, &message, message.Type, ""))
+	}
+}
+
+func (wh responseHandler) HandleLocationMessage(message whatsapp.LocationMessage) {
+	wh.received("location", message.Info, message.Name)
+
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling location message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "location", message.Info, message, nil, "", ""))
+	}
+}
+
+func (wh responseHandler) HandleLiveLocationMessage(message whatsapp.LiveLocationMessage) {
+	wh.received("live_location", message.Info, message.Caption)
+
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling live location message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "live_location", message.Info, message, nil, "", ""))
+	}
+}
+
+func (wh responseHandler) HandleContactMessage(message whatsapp.ContactMessage) {
+	wh.received("contact", message.Info, message.DisplayName)
+
+	if wh.accepts(message.Info) {
+		fmt.Printf("[+] Handling contact message\n")
+		publishEvent(wh.jid, EventMessage, waWebhookMessage(wh.jid, "contact", message.Info, message, nil, "", ""))
 	}
 }
 
 func (wh responseHandler) HandleJsonMessage(message string) {
 	fmt.Printf("[+] %v\n", message)
+
+	receiptJSON(wh.jid, message)
 }
 
-var wac = make(map[string]*whatsapp.Conn)
+func (wh responseHandler) HandleContactList(contacts []whatsapp.Contact) {
+	rosterContactList(wh.jid, contacts)
+}
+
+func (wh responseHandler) HandleNewContact(contact whatsapp.Contact) {
+	rosterContactPut(wh.jid, contact)
+}
+
+func (wh responseHandler) HandleChatList(chats []whatsapp.Chat) {
+	rosterChatList(wh.jid, chats)
+}
+
+var wac = newRegistry()
 
 func WASyncVersion(conn *whatsapp.Conn) (string, error) {
 	versionServer, err := whatsapp.CheckCurrentServerVersion()
@@ -121,24 +182,37 @@ func WASyncVersion(conn *whatsapp.Conn) (string, error) {
 }
 
 func WAInit(jid string, timeout int) error {
-	if wac[jid] == nil {
-		conn, err := whatsapp.NewConn(time.Duration(timeout) * time.Second)
+	_, err := wac.Init(jid, func() (Client, error) {
+		conn, err := NewClient(timeout)
 		if err != nil {
-			return err
-		}
-		conn.SetClientName("Go WhatsApp REST", "Go WhatsApp")
-		
-		info, err := WASyncVersion(conn)
-		if err != nil {
-			return err
+			return nil, err
 		}
-		
-		fmt.Printf("[+] %v\n", info)
-		
-		wac[jid] = conn
+
+		conn.AddHandler(responseHandler{jid: jid, client: conn})
+
+		return conn, nil
+	})
+
+	return err
+}
+
+func WASessionInfo(jid string) (SessionInfo, bool) {
+	return wac.Info(jid)
+}
+
+// WASessionFile Function Returns The File The Session of a JID is Stored in
+func WASessionFile(jid string) string {
+	return svc.Config.GetString("SERVER_STORE_PATH") + "/" + jid + ".gob"
+}
+
+// waSelfJID Function Returns The JID an Account is Logged in With
+func waSelfJID(jid string) (string, error) {
+	session, err := WASessionLoad(WASessionFile(jid))
+	if err != nil || len(session.Wid) == 0 {
+		return "", ErrNotLoggedIn
 	}
 
-	return nil
+	return waJidNormalize(session.Wid), nil
 }
 
 func WASessionLoad(file string) (whatsapp.Session, error) {
@@ -174,7 +248,7 @@ func WASessionSave(file string, session whatsapp.Session) error {
 }
 
 func WASessionLogin(jid string, file string, qr chan<- string) error {
-	if wac[jid] != nil {
+	if conn := wac.Get(jid); conn != nil {
 		_, err := os.Stat(file)
 		if err == nil {
 			err = os.Remove(file)
@@ -183,65 +257,75 @@ func WASessionLogin(jid string, file string, qr chan<- string) error {
 			}
 		}
 
-		session, err := wac[jid].Login(qr)
+		session, err := conn.Login(qr)
 		if err != nil {
-			switch strings.ToLower(err.Error()) {
-			case "already logged in":
+			err = waErrorWrap(err)
+
+			switch {
+			case errors.Is(err, whatsapp.ErrAlreadyLoggedIn):
+				wac.SetState(jid, StateConnected)
 				return nil
-			case "could not send proto: failed to write message: error writing to websocket: websocket: close sent":
-				delete(wac, jid)
-				return errors.New("connection is invalid")
+			case errors.Is(err, ErrNotConnected):
+				wac.Remove(jid, StateDisconnected)
+				return err
 			default:
 				return err
 			}
 		}
 
+		wac.SetState(jid, StateConnected)
+
 		err = WASessionSave(file, session)
 		if err != nil {
 			return err
 		}
 	} else {
-		return errors.New("connection is invalid")
+		return ErrNotConnected
 	}
 
 	return nil
 }
 
 func WASessionRestore(jid string, file string, sess whatsapp.Session) error {
-	if wac[jid] != nil {
-		session, err := wac[jid].RestoreWithSession(sess)
+	if conn := wac.Get(jid); conn != nil {
+		session, err := conn.RestoreWithSession(sess)
 		if err != nil {
-			switch strings.ToLower(err.Error()) {
-			case "already logged in":
+			err = waErrorWrap(err)
+
+			switch {
+			case errors.Is(err, whatsapp.ErrAlreadyLoggedIn):
+				wac.SetState(jid, StateConnected)
 				return nil
-			case "could not send proto: failed to write message: error writing to websocket: websocket: close sent":
-				delete(wac, jid)
-				return errors.New("connection is invalid")
+			case errors.Is(err, ErrNotConnected):
+				wac.Remove(jid, StateDisconnected)
+				return err
 			default:
-				errLogout := wac[jid].Logout()
+				errLogout := conn.Logout()
 				if errLogout != nil {
 					return errLogout
 				}
 
-				delete(wac, jid)
+				wac.Remove(jid, StateLoggedOut)
 				return err
 			}
 		}
 
+		wac.SetState(jid, StateConnected)
+
 		err = WASessionSave(file, session)
 		if err != nil {
 			return err
 		}
 	} else {
-		return errors.New("connection is invalid")
+		return ErrNotConnected
 	}
 
 	return nil
 }
 
 func WASessionLogout(jid string, file string) error {
-	if wac[jid] != nil {
-		err := wac[jid].Logout()
+	if conn := wac.Get(jid); conn != nil {
+		err := conn.Logout()
 		if err != nil {
 			return err
 		}
@@ -254,144 +338,241 @@ func WASessionLogout(jid string, file string) error {
 			}
 		}
 
-		delete(wac, jid)
+		waUnsupervise(jid)
+		wac.Remove(jid, StateLoggedOut)
+		rosterClear(jid)
 	} else {
-		return errors.New("connection is invalid")
+		return ErrNotConnected
 	}
 
 	return nil
 }
 
-func WAConnect(jid string, webhook string, timeout int, file string, qrstr chan<- string, errmsg chan<- error) {
-	if wac[jid] != nil {
-		chanqr := make(chan string)
-		go func() {
-			select {
-			case tmp := <-chanqr:
-				png, errPNG := qrcode.Encode(tmp, qrcode.Medium, 256)
-				if errPNG != nil {
-					errmsg <- errPNG
-					return
-				}
+func WAMessageText(jid string, msgID string, jidDest string, msgText string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-				qrstr <- base64.StdEncoding.EncodeToString(png)
-			case <-time.After(time.Duration(timeout) * time.Second):
-				errmsg <- errors.New("qr code generate timed out")
-			}
-		}()
+	return waMessageSend(jid, remoteJid, whatsapp.TextMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Text:        msgText,
+		ContextInfo: msgContext,
+	})
+}
 
-		if len(webhook) > 0 {
-			fmt.Printf("[!] removing webhooks\n")
-			wac[jid].RemoveHandlers()
+func WAMessageImage(jid string, msgID string, jidDest string, msgImageStream io.Reader, msgImageType string, msgCaption string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-			fmt.Printf("[!] adding webhook: %v\n", webhook)
-			wac[jid].AddHandler(responseHandler{webhook, uint64(time.Now().Unix())})
-		}
+	return waMessageSend(jid, remoteJid, whatsapp.ImageMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Content:     msgImageStream,
+		Type:        msgImageType,
+		Caption:     msgCaption,
+		ContextInfo: msgContext,
+	})
+}
 
-		session, err := WASessionLoad(file)
-		if err != nil {
-			err = WASessionLogin(jid, file, chanqr)
-			if err != nil {
-				errmsg <- err
-				return
-			}
-		} else {
-			err = WASessionRestore(jid, file, session)
-			if err != nil {
-				err := WAInit(jid, timeout)
-				if err != nil {
-					errmsg <- err
-					return
-				}
+func WAMessageDocument(jid string, msgID string, jidDest string, msgDocumentStream io.Reader, msgDocumentType string, msgFileName string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-				err = WASessionLogin(jid, file, chanqr)
-				if err != nil {
-					errmsg <- err
-					return
-				}
-			}
-		}
-	} else {
-		errmsg <- errors.New("connection is invalid")
-		return
+	return waMessageSend(jid, remoteJid, whatsapp.DocumentMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Content:     msgDocumentStream,
+		Type:        msgDocumentType,
+		Title:       msgFileName,
+		FileName:    msgFileName,
+		ContextInfo: msgContext,
+	})
+}
+
+func WAMessageAudio(jid string, msgID string, jidDest string, msgAudioStream io.Reader, msgAudioType string, msgPTT bool, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
 	}
 
-	errmsg <- errors.New("")
-	return
+	return waMessageSend(jid, remoteJid, whatsapp.AudioMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Content:     msgAudioStream,
+		Type:        msgAudioType,
+		Ptt:         msgPTT,
+		ContextInfo: msgContext,
+	})
 }
 
-func WAMessageText(jid string, jidDest string, msgText string, msgDelay int) error {
-	if wac[jid] != nil {
-		jidPrefix := "@s.whatsapp.net"
-		if len(strings.SplitN(jidDest, "-", 2)) == 2 {
-			jidPrefix = "@g.us"
-		}
+func WAMessageVideo(jid string, msgID string, jidDest string, msgVideoStream io.Reader, msgVideoType string, msgCaption string, msgThumbnail []byte, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-		content := whatsapp.TextMessage{
-			Info: whatsapp.MessageInfo{
-				RemoteJid: jidDest + jidPrefix,
-			},
-			Text: msgText,
-		}
+	return waMessageSend(jid, remoteJid, whatsapp.VideoMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Content:     msgVideoStream,
+		Type:        msgVideoType,
+		Caption:     msgCaption,
+		Thumbnail:   msgThumbnail,
+		ContextInfo: msgContext,
+	})
+}
 
-		_, _ = wac[jid].Presence(jidDest + jidPrefix, "composing")
+func WAMessageSticker(jid string, msgID string, jidDest string, msgStickerStream io.Reader, msgStickerType string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-		<-time.After(time.Duration(msgDelay) * time.Second)
+	return waMessageSend(jid, remoteJid, whatsapp.StickerMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		Content:     msgStickerStream,
+		Type:        msgStickerType,
+		ContextInfo: msgContext,
+	})
+}
 
-		_, err := wac[jid].Send(content)
-		if err != nil {
-			switch strings.ToLower(err.Error()) {
-			case "sending message timed out":
-				return nil
-			case "could not send proto: failed to write message: error writing to websocket: websocket: close sent":
-				delete(wac, jid)
-				return errors.New("connection is invalid")
-			default:
-				return err
-			}
-		}
-	} else {
-		return errors.New("connection is invalid")
+func WAMessageLocation(jid string, msgID string, jidDest string, msgLatitude float64, msgLongitude float64, msgName string, msgAddress string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return waMessageSend(jid, remoteJid, whatsapp.LocationMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		DegreesLatitude:  msgLatitude,
+		DegreesLongitude: msgLongitude,
+		Name:             msgName,
+		Address:          msgAddress,
+		ContextInfo:      msgContext,
+	})
 }
 
-func WAMessageImage(jid string, jidDest string, msgImageStream multipart.File, msgImageType string, msgCaption string, msgDelay int) error {
-	if wac[jid] != nil {
-		jidPrefix := "@s.whatsapp.net"
-		if len(strings.SplitN(jidDest, "-", 2)) == 2 {
-			jidPrefix = "@g.us"
-		}
+func WAMessageContact(jid string, msgID string, jidDest string, msgDisplayName string, msgVCard string, msgContext whatsapp.ContextInfo) error {
+	remoteJid, err := waSendJid(jidDest)
+	if err != nil {
+		return err
+	}
 
-		content := whatsapp.ImageMessage{
-			Info: whatsapp.MessageInfo{
-				RemoteJid: jidDest + jidPrefix,
-			},
-			Content: msgImageStream,
-			Type:    msgImageType,
-			Caption: msgCaption,
-		}
+	return waMessageSend(jid, remoteJid, whatsapp.ContactMessage{
+		Info: whatsapp.MessageInfo{
+			Id:        msgID,
+			RemoteJid: remoteJid,
+		},
+		DisplayName: msgDisplayName,
+		Vcard:       msgVCard,
+		ContextInfo: msgContext,
+	})
+}
+
+// waRemoteJid Function Normalizes a Phone Number, Group ID or JID Into The
+// JID a Message is Sent to
+func waRemoteJid(jidDest string) (string, error) {
+	remote, err := wajid.Parse(jidDest)
+	if err != nil {
+		return "", &waError{kind: ErrInvalidRecipient, err: err}
+	}
+
+	return remote.String(), nil
+}
+
+// waSendJid Function Normalizes The Recipient of an Outgoing Message Like
+// waRemoteJid, Rejecting Broadcast Lists and The Status Updates
+func waSendJid(jidDest string) (string, error) {
+	remote, err := wajid.Parse(jidDest)
+	if err != nil {
+		return "", &waError{kind: ErrInvalidRecipient, err: err}
+	}
 
-		_, _ = wac[jid].Presence(jidDest + jidPrefix, "composing")
+	if remote.IsBroadcast() {
+		return "", &waError{kind: ErrInvalidRecipient, err: errors.New("messages can not be sent to " + remote.String())}
+	}
 
-		<-time.After(time.Duration(msgDelay) * time.Second)
+	return remote.String(), nil
+}
 
-		_, err := wac[jid].Send(content)
+func waMessageSend(jid string, remoteJid string, content interface{}) error {
+	if conn := wac.Get(jid); conn != nil {
+		_, _ = conn.Presence(remoteJid, whatsapp.PresenceComposing)
+
+		_, err := conn.Send(content)
 		if err != nil {
-			switch strings.ToLower(err.Error()) {
-			case "sending message timed out":
-				return nil
-			case "could not send proto: failed to write message: error writing to websocket: websocket: close sent":
-				delete(wac, jid)
-				return errors.New("connection is invalid")
-			default:
-				return err
+			err = waErrorWrap(err)
+			if errors.Is(err, ErrNotConnected) {
+				waDisconnected(jid, conn, err)
 			}
+
+			return err
 		}
+
+		rosterChatUpdate(jid, remoteJid, func(chat *Chat) bool {
+			chat.LastMessageAt = time.Now()
+			chat.Unread = 0
+
+			return true
+		})
 	} else {
-		return errors.New("connection is invalid")
+		return ErrNotConnected
 	}
 
 	return nil
 }
+
+// waQueryTimeout is How Long WhatsApp Gets to Answer a Query
+var waQueryTimeout = 10 * time.Second
+
+// waQuery Function Waits For The Answer to a Query Sent Through a Client
+func waQuery(ch <-chan string, err error) ([]byte, error) {
+	return waQueryWithin(ch, err, waQueryTimeout)
+}
+
+// waQueryWithin Function Waits at Most timeout For The Answer to a Query
+func waQueryWithin(ch <-chan string, err error, timeout time.Duration) ([]byte, error) {
+	if err != nil {
+		return nil, waErrorWrap(err)
+	}
+
+	select {
+	case data := <-ch:
+		return []byte(data), nil
+	case <-time.After(timeout):
+		return nil, &waError{kind: ErrSendTimeout, err: errors.New("query timed out")}
+	}
+}
+
+// waJidNormalize Function Normalizes a JID Sent by WhatsApp, Keeping it as
+// it is When it Can Not be Parsed
+func waJidNormalize(s string) string {
+	remote, err := wajid.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	return remote.String()
+}
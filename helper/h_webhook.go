@@ -0,0 +1,842 @@
+package helper
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/webhook"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Webhook Delivery Policy
+var (
+	WebhookWorkers       = 4
+	WebhookMaxAttempts   = 5
+	WebhookRetryDelay    = 5 * time.Second
+	WebhookRetryDelayMax = 10 * time.Minute
+	WebhookTimeout       = 10 * time.Second
+)
+
+// webhookQueueSize is How Many Deliveries Can Wait For a Free Worker
+const webhookQueueSize = 1024
+
+// webhookIdle is How Long The Scheduler Sleeps When Nothing is Due
+const webhookIdle = time.Minute
+
+// Store Buckets Keeping Deliveries Waiting For an Attempt and
+// Deliveries That Exhausted Their Attempts
+const (
+	bucketWebhookPending = "webhookpending"
+	bucketWebhookFailed  = "webhookfailed"
+)
+
+// WebhookDelivery Struct Describing a Single Webhook Request
+type WebhookDelivery struct {
+	ID            string          `json:"id"`
+	JID           string          `json:"jid"`
+	Subscriber    string          `json:"subscriber"`
+	Event         string          `json:"event"`
+	URL           string          `json:"url"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	StatusCode    int             `json:"status_code,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// WebhookSubscriber Struct Describing a Webhook Registered by an Account
+// An Empty Events List Subscribes to Every Event. Subscribers Are Posted
+// The Data of an Event Alone Unless They Ask For The Whole Envelope
+type WebhookSubscriber struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events,omitempty"`
+	Envelope  bool      `json:"envelope,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var webhookSubscribers = struct {
+	sync.Mutex
+	m map[string][]WebhookSubscriber
+}{m: make(map[string][]WebhookSubscriber)}
+
+var webhookJobs = make(chan WebhookDelivery, webhookQueueSize)
+
+// webhookInflight Keeps The IDs of Pending Deliveries Handed to a Worker
+var webhookInflight = struct {
+	sync.Mutex
+	m map[string]bool
+}{m: make(map[string]bool)}
+
+var webhookWakeup = make(chan struct{}, 1)
+
+var webhookClient = &http.Client{}
+
+// WebhookMessage Struct is The Envelope Posted to a Webhook for Every
+// Incoming Message Regardless of its Type
+type WebhookMessage struct {
+	JID        string               `json:"jid"`
+	Type       string               `json:"type"`
+	Info       whatsapp.MessageInfo `json:"info"`
+	Message    interface{}          `json:"message"`
+	Media      string               `json:"media,omitempty"`
+	MediaError string               `json:"media_error,omitempty"`
+	Response   *DialogResponse      `json:"response,omitempty"`
+	ReceivedAt time.Time            `json:"received_at"`
+}
+
+// waMediaDownloader Interface Implemented by Every Incoming Media Message
+type waMediaDownloader interface {
+	Download() ([]byte, error)
+}
+
+// waWebhookMessage Function Builds The Envelope for an Incoming Message and
+// Stores its Media in SERVER_UPLOAD_PATH When it Carries Any
+func waWebhookMessage(jid string, msgType string, info whatsapp.MessageInfo, message interface{}, media waMediaDownloader, mediaType string, fileName string) WebhookMessage {
+	envelope := WebhookMessage{
+		JID:        jid,
+		Type:       msgType,
+		Info:       info,
+		Message:    message,
+		ReceivedAt: time.Now().UTC(),
+	}
+
+	if media != nil {
+		file, err := waMediaStore(info.Id, media, mediaType, fileName)
+		if err != nil {
+			fmt.Printf("[!] %v\n", err)
+			envelope.MediaError = err.Error()
+		} else {
+			fmt.Printf("[!] stored: %v\n", file)
+			envelope.Media = file
+
+			historyMedia(jid, info.Id, file, mediaType, fileName)
+		}
+	}
+
+	return envelope
+}
+
+// waMediaStore Function Downloads The Media of an Incoming Message and Writes
+// it to SERVER_UPLOAD_PATH Named After The Message ID
+func waMediaStore(id string, media waMediaDownloader, mediaType string, fileName string) (string, error) {
+	data, err := media.Download()
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if len(ext) == 0 {
+		if baseType, _, err := mime.ParseMediaType(mediaType); err == nil {
+			if i := strings.Index(baseType, "/"); i >= 0 {
+				ext = "." + baseType[i+1:]
+			}
+		}
+	}
+
+	file := fmt.Sprintf("%v/%v%v", svc.Config.GetString("SERVER_UPLOAD_PATH"), id, ext)
+
+	err = ioutil.WriteFile(file, data, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// WAWebhookStart Function Starts The Worker Pool Delivering Webhooks and
+// Resumes The Deliveries Left Pending by a Previous Run
+func WAWebhookStart() {
+	webhookClient.Timeout = WebhookTimeout
+
+	for i := 0; i < WebhookWorkers; i++ {
+		go webhookWork()
+	}
+
+	go webhookSchedule()
+}
+
+// WAWebhookFailed Function Returns Every Failed Delivery of a JID
+func WAWebhookFailed(jid string) ([]WebhookDelivery, error) {
+	deliveries := make([]WebhookDelivery, 0)
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketWebhookFailed, jid)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var delivery WebhookDelivery
+
+			err := json.Unmarshal(v, &delivery)
+			if err != nil {
+				return err
+			}
+
+			deliveries = append(deliveries, delivery)
+			return nil
+		})
+	})
+
+	return deliveries, err
+}
+
+// WAWebhookReplay Function Takes a Failed Delivery Out of The Dead-Letter
+// Store and Dispatches it Again With a Fresh Set of Attempts
+func WAWebhookReplay(jid string, id string) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		bucket := storeBucketRead(tx, bucketWebhookFailed, jid)
+		if bucket == nil {
+			return fmt.Errorf("delivery %w", ErrNotFound)
+		}
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("delivery %w", ErrNotFound)
+		}
+
+		err := json.Unmarshal(data, &delivery)
+		if err != nil {
+			return err
+		}
+
+		err = bucket.Delete([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		delivery.Attempts = 0
+		delivery.StatusCode = 0
+		delivery.Error = ""
+		delivery.NextAttemptAt = now
+		delivery.UpdatedAt = now
+
+		return webhookPendingPut(tx, delivery)
+	})
+	if err != nil {
+		return delivery, err
+	}
+
+	webhookWake()
+
+	return delivery, nil
+}
+
+// WAWebhookList Function Returns The Webhook Subscribers of a JID
+func WAWebhookList(jid string) ([]WebhookSubscriber, error) {
+	webhookSubscribers.Lock()
+	defer webhookSubscribers.Unlock()
+
+	subscribers, err := webhookLoad(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]WebhookSubscriber{}, subscribers...), nil
+}
+
+// WAWebhookPut Function Registers a Webhook Subscriber of a JID, Replacing
+// The Subscriber With The Same URL When There is One. Subscribers Registered
+// Without a Secret Get a Generated One so Every Delivery is Signed
+func WAWebhookPut(jid string, subscriber WebhookSubscriber) (WebhookSubscriber, error) {
+	parsed, err := url.Parse(subscriber.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return subscriber, &waError{kind: ErrInvalidArgument, err: errors.New("webhook url is invalid")}
+	}
+
+	if len(subscriber.Secret) == 0 {
+		subscriber.Secret = webhookSecret()
+	}
+
+	webhookSubscribers.Lock()
+	defer webhookSubscribers.Unlock()
+
+	subscribers, err := webhookLoad(jid)
+	if err != nil {
+		return subscriber, err
+	}
+
+	now := time.Now()
+
+	subscriber.ID = webhookID()
+	subscriber.CreatedAt = now
+	subscriber.UpdatedAt = now
+
+	updated := append([]WebhookSubscriber{}, subscribers...)
+
+	replaced := false
+	for i, existing := range updated {
+		if existing.URL == subscriber.URL {
+			subscriber.ID = existing.ID
+			subscriber.CreatedAt = existing.CreatedAt
+			updated[i] = subscriber
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		updated = append(updated, subscriber)
+	}
+
+	return subscriber, webhookSave(jid, updated)
+}
+
+// WAWebhookDelete Function Removes a Webhook Subscriber of a JID,
+// or Every Subscriber When id is Empty
+func WAWebhookDelete(jid string, id string) error {
+	webhookSubscribers.Lock()
+	defer webhookSubscribers.Unlock()
+
+	subscribers, err := webhookLoad(jid)
+	if err != nil {
+		return err
+	}
+
+	updated := make([]WebhookSubscriber, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if len(id) > 0 && subscriber.ID != id {
+			updated = append(updated, subscriber)
+		}
+	}
+
+	if len(id) > 0 && len(updated) == len(subscribers) {
+		return fmt.Errorf("webhook %w", ErrNotFound)
+	}
+
+	return webhookSave(jid, updated)
+}
+
+// waWebhookWants Function Reports Whether Any Subscriber of a JID Wants an Event
+func waWebhookWants(jid string, eventName string) bool {
+	return len(webhookMatching(jid, eventName)) > 0
+}
+
+// webhookNotify Function Queues an Event For Delivery to Every Subscriber
+// of its JID Whose Filter Matches it
+func webhookNotify(event Event) {
+	eventName := webhookEventName(event)
+
+	subscribers := webhookMatching(event.JID, eventName)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return
+	}
+
+	envelope, err := json.Marshal(event)
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return
+	}
+
+	now := time.Now()
+
+	deliveries := make([]WebhookDelivery, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		payload := data
+		if subscriber.Envelope {
+			payload = envelope
+		}
+
+		deliveries = append(deliveries, WebhookDelivery{
+			ID:            webhookID(),
+			JID:           event.JID,
+			Subscriber:    subscriber.ID,
+			Event:         eventName,
+			URL:           subscriber.URL,
+			Payload:       payload,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	webhookDispatch(deliveries...)
+}
+
+// webhookEventName Function Returns The Name Filters Match an Event Against,
+// Incoming Messages Are Named After Their Type Such as message.text
+func webhookEventName(event Event) string {
+	switch data := event.Data.(type) {
+	case WebhookMessage:
+		return event.Type + "." + data.Type
+	case MessageReceipt:
+		return event.Type + "." + data.Receipt
+	}
+
+	return event.Type
+}
+
+// webhookMatching Function Returns The Subscribers of a JID Wanting an Event
+// A Filter Matches The Event Name Itself or Any Name Below it
+func webhookMatching(jid string, eventName string) []WebhookSubscriber {
+	webhookSubscribers.Lock()
+	subscribers, err := webhookLoad(jid)
+	webhookSubscribers.Unlock()
+
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return nil
+	}
+
+	var matching []WebhookSubscriber
+	for _, subscriber := range subscribers {
+		if len(subscriber.Events) == 0 {
+			matching = append(matching, subscriber)
+			continue
+		}
+
+		for _, filter := range subscriber.Events {
+			if filter == eventName || strings.HasPrefix(eventName, filter+".") {
+				matching = append(matching, subscriber)
+				break
+			}
+		}
+	}
+
+	return matching
+}
+
+// webhookSubscriber Function Returns a Subscriber of a JID by its ID
+func webhookSubscriber(jid string, id string) (WebhookSubscriber, bool) {
+	webhookSubscribers.Lock()
+	subscribers, err := webhookLoad(jid)
+	webhookSubscribers.Unlock()
+
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return WebhookSubscriber{}, false
+	}
+
+	for _, subscriber := range subscribers {
+		if subscriber.ID == id {
+			return subscriber, true
+		}
+	}
+
+	return WebhookSubscriber{}, false
+}
+
+func webhookFile(jid string) string {
+	return filepath.Join(svc.Config.GetString("SERVER_STORE_PATH"), jid+".webhooks.json")
+}
+
+// webhookForget Function Deletes The Subscribers of a JID
+func webhookForget(jid string) error {
+	webhookSubscribers.Lock()
+	defer webhookSubscribers.Unlock()
+
+	delete(webhookSubscribers.m, jid)
+
+	err := os.Remove(webhookFile(jid))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// webhookLoad Function Returns The Subscribers of a JID, Reading Them From
+// The Store Path The First Time. Must be Called With The Lock Held
+func webhookLoad(jid string) ([]WebhookSubscriber, error) {
+	if subscribers, ok := webhookSubscribers.m[jid]; ok {
+		return subscribers, nil
+	}
+
+	var subscribers []WebhookSubscriber
+
+	data, err := ioutil.ReadFile(webhookFile(jid))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		err = json.Unmarshal(data, &subscribers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	webhookSubscribers.m[jid] = subscribers
+
+	return subscribers, nil
+}
+
+// webhookSave Function Writes The Subscribers of a JID to The Store Path
+// Must be Called With The Lock Held
+func webhookSave(jid string, subscribers []WebhookSubscriber) error {
+	file := webhookFile(jid)
+
+	if len(subscribers) == 0 {
+		err := os.Remove(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		data, err := json.MarshalIndent(subscribers, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		// Write Then Rename so a Crash Never Leaves a Truncated File
+		err = ioutil.WriteFile(file+".tmp", data, 0600)
+		if err != nil {
+			return err
+		}
+
+		err = os.Rename(file+".tmp", file)
+		if err != nil {
+			return err
+		}
+	}
+
+	webhookSubscribers.m[jid] = subscribers
+
+	return nil
+}
+
+// webhookDispatch Function Stores Deliveries as Pending so They Survive
+// a Restart, Then Wakes The Scheduler to Hand Them to The Worker Pool
+func webhookDispatch(deliveries ...WebhookDelivery) {
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		for _, delivery := range deliveries {
+			err := webhookPendingPut(tx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return
+	}
+
+	webhookWake()
+}
+
+// webhookWake Function Signals The Scheduler to Look For Due Deliveries
+func webhookWake() {
+	select {
+	case webhookWakeup <- struct{}{}:
+	default:
+	}
+}
+
+func webhookSchedule() {
+	for {
+		wait := webhookScheduleDue()
+
+		select {
+		case <-webhookWakeup:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// webhookScheduleDue Function Hands Every Due Delivery to The Worker Pool
+// and Returns How Long to Wait Before Looking Again
+func webhookScheduleDue() time.Duration {
+	due, wait, err := webhookDue(time.Now())
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+		return WebhookRetryDelay
+	}
+
+	for _, delivery := range due {
+		if !webhookEnqueue(delivery) {
+			svc.Log("warn", "webhook", "webhook queue is full, delaying pending deliveries")
+			return WebhookRetryDelay
+		}
+	}
+
+	return wait
+}
+
+// webhookDue Function Returns The Pending Deliveries Due at now That no Worker
+// Holds Yet, and How Long Until The Next One is Due
+func webhookDue(now time.Time) ([]WebhookDelivery, time.Duration, error) {
+	var due []WebhookDelivery
+	wait := webhookIdle
+
+	webhookInflight.Lock()
+	defer webhookInflight.Unlock()
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		pending := tx.Bucket([]byte(bucketWebhookPending))
+		if pending == nil {
+			return nil
+		}
+
+		return pending.ForEach(func(jid, _ []byte) error {
+			bucket := pending.Bucket(jid)
+			if bucket == nil {
+				return nil
+			}
+
+			return bucket.ForEach(func(k, v []byte) error {
+				if webhookInflight.m[string(k)] {
+					return nil
+				}
+
+				var delivery WebhookDelivery
+
+				err := json.Unmarshal(v, &delivery)
+				if err != nil {
+					return err
+				}
+
+				if !delivery.NextAttemptAt.After(now) {
+					due = append(due, delivery)
+					return nil
+				}
+
+				if d := delivery.NextAttemptAt.Sub(now); d < wait {
+					wait = d
+				}
+
+				return nil
+			})
+		})
+	})
+
+	return due, wait, err
+}
+
+// webhookEnqueue Function Hands a Pending Delivery to The Worker Pool Without
+// Blocking and Reports Whether There Was Room For it
+func webhookEnqueue(delivery WebhookDelivery) bool {
+	webhookInflight.Lock()
+	defer webhookInflight.Unlock()
+
+	select {
+	case webhookJobs <- delivery:
+		webhookInflight.m[delivery.ID] = true
+		return true
+	default:
+		return false
+	}
+}
+
+// webhookRelease Function Lets The Scheduler Pick a Delivery up Again
+// Once its Pending Record Was Updated
+func webhookRelease(delivery WebhookDelivery) {
+	webhookInflight.Lock()
+	delete(webhookInflight.m, delivery.ID)
+	webhookInflight.Unlock()
+}
+
+func webhookWork() {
+	for delivery := range webhookJobs {
+		webhookDeliver(delivery)
+	}
+}
+
+// webhookDeliver Function Attempts a Delivery Once, Scheduling a Retry With
+// Backoff or Moving it to The Dead-Letter Store When it Fails
+func webhookDeliver(delivery WebhookDelivery) {
+	subscriber, ok := webhookSubscriber(delivery.JID, delivery.Subscriber)
+	if !ok {
+		svc.Log("info", "webhook", fmt.Sprintf("delivery %v dropped, webhook %v was removed", delivery.ID, delivery.Subscriber))
+		webhookDone(delivery)
+		return
+	}
+
+	delivery.URL = subscriber.URL
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now()
+
+	statusCode, err := webhookSend(delivery, subscriber.Secret)
+	delivery.StatusCode = statusCode
+	if err == nil {
+		webhookDone(delivery)
+		return
+	}
+
+	delivery.Error = err.Error()
+
+	if delivery.Attempts >= WebhookMaxAttempts {
+		webhookFail(delivery)
+		return
+	}
+
+	delivery.NextAttemptAt = delivery.UpdatedAt.Add(backoff(WebhookRetryDelay, WebhookRetryDelayMax, delivery.Attempts-1))
+
+	webhookRetry(delivery)
+}
+
+// webhookSend Function Posts a Delivery, Treating Any Non-2xx Status as a Failure
+func webhookSend(delivery WebhookDelivery, secret string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderID, delivery.ID)
+	req.Header.Set(webhook.HeaderEvent, delivery.Event)
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+
+	if len(secret) > 0 {
+		req.Header.Set(webhook.HeaderSignature, webhook.Sign(secret, delivery.ID, timestamp, delivery.Payload))
+	}
+
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// webhookPendingPut Function Stores a Delivery Waiting For an Attempt
+func webhookPendingPut(tx *bolt.Tx, delivery WebhookDelivery) error {
+	bucket, err := storeBucket(tx, bucketWebhookPending, delivery.JID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(delivery.ID), data)
+}
+
+// webhookPendingDelete Function Removes a Delivery Waiting For an Attempt
+func webhookPendingDelete(tx *bolt.Tx, delivery WebhookDelivery) error {
+	bucket := storeBucketRead(tx, bucketWebhookPending, delivery.JID)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.Delete([]byte(delivery.ID))
+}
+
+// webhookPendingHas Function Reports Whether a Delivery is Still Pending,
+// Which it is Not Once its Account Got Deleted
+func webhookPendingHas(tx *bolt.Tx, delivery WebhookDelivery) bool {
+	bucket := storeBucketRead(tx, bucketWebhookPending, delivery.JID)
+
+	return bucket != nil && bucket.Get([]byte(delivery.ID)) != nil
+}
+
+// webhookDone Function Forgets a Delivery That Needs no More Attempts
+func webhookDone(delivery WebhookDelivery) {
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		return webhookPendingDelete(tx, delivery)
+	})
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+	}
+
+	webhookRelease(delivery)
+}
+
+// webhookRetry Function Stores The Next Attempt of a Delivery
+func webhookRetry(delivery WebhookDelivery) {
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		if !webhookPendingHas(tx, delivery) {
+			return nil
+		}
+
+		return webhookPendingPut(tx, delivery)
+	})
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+	}
+
+	webhookRelease(delivery)
+	webhookWake()
+}
+
+// webhookFail Function Moves a Delivery to The Dead-Letter Store
+func webhookFail(delivery WebhookDelivery) {
+	svc.Log("error", "webhook", fmt.Sprintf("delivery %v to %v failed: %v", delivery.ID, delivery.URL, delivery.Error))
+
+	err := svc.Store.Update(func(tx *bolt.Tx) error {
+		if !webhookPendingHas(tx, delivery) {
+			return nil
+		}
+
+		err := webhookPendingDelete(tx, delivery)
+		if err != nil {
+			return err
+		}
+
+		bucket, err := storeBucket(tx, bucketWebhookFailed, delivery.JID)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(delivery)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(delivery.ID), data)
+	})
+	if err != nil {
+		svc.Log("error", "webhook", err.Error())
+	}
+
+	webhookRelease(delivery)
+}
+
+// webhookID Function Generates a Random Delivery ID
+func webhookID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// webhookSecret Function Generates a Random Signing Secret
+func webhookSecret() string {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	return hex.EncodeToString(secret)
+}
@@ -0,0 +1,390 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/webhook"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+	bolt "go.etcd.io/bbolt"
+)
+
+var testWebhookOnce sync.Once
+
+// testWebhookStart Function Starts The Worker Pool Once For Every Test
+// With Retries Short Enough to Wait For
+func testWebhookStart() {
+	testWebhookOnce.Do(func() {
+		WebhookRetryDelay = 10 * time.Millisecond
+		WebhookRetryDelayMax = 50 * time.Millisecond
+
+		WAWebhookStart()
+	})
+}
+
+// testWebhookServer Function Starts a Webhook Receiver Answering With
+// The Status Returned by status For Every Request it Counts
+func testWebhookServer(t *testing.T, status func(request int32) int) (*httptest.Server, *int32) {
+	var requests int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status(atomic.AddInt32(&requests, 1)))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+// testWebhookPending Function Counts The Pending Deliveries of a JID
+func testWebhookPending(t *testing.T, jid string) int {
+	count := 0
+
+	err := svc.Store.View(func(tx *bolt.Tx) error {
+		if bucket := storeBucketRead(tx, bucketWebhookPending, jid); bucket != nil {
+			count = bucket.Stats().KeyN
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestWebhookMessage(t *testing.T) {
+	testWebhookStart()
+
+	bodies := make(chan WebhookMessage, 2)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body WebhookMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	jid := "webhook-message-" + webhookID()
+
+	_, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL, Events: []string{"message.location"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := responseHandler{jid: jid}
+	handler.HandleContactMessage(whatsapp.ContactMessage{
+		Info: whatsapp.MessageInfo{Id: "WEBHOOK0001", RemoteJid: "6281111111111@s.whatsapp.net"},
+	})
+	handler.HandleLocationMessage(whatsapp.LocationMessage{
+		Info: whatsapp.MessageInfo{Id: "WEBHOOK0002", RemoteJid: "6281111111111@s.whatsapp.net", FromMe: true},
+	})
+	handler.HandleLocationMessage(whatsapp.LocationMessage{
+		Info:             whatsapp.MessageInfo{Id: "WEBHOOK0003", RemoteJid: "6281111111111@s.whatsapp.net"},
+		DegreesLatitude:  -6.2,
+		DegreesLongitude: 106.8,
+	})
+
+	select {
+	case body := <-bodies:
+		if body.JID != jid || body.Type != "location" || body.Info.Id != "WEBHOOK0003" {
+			t.Fatalf("unexpected webhook body %+v", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	select {
+	case body := <-bodies:
+		t.Fatalf("unwanted message was forwarded: %+v", body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookSigned(t *testing.T) {
+	testWebhookStart()
+
+	verifier := webhook.NewVerifier("signing-secret")
+	verified := make(chan error, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := verifier.Verify(r)
+		verified <- err
+	}))
+	defer srv.Close()
+
+	jid := "webhook-signed-" + webhookID()
+
+	_, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL, Secret: "signing-secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookNotify(Event{JID: jid, Type: EventState, Data: SessionInfo{State: StateConnected}, Time: time.Now()})
+
+	select {
+	case err := <-verified:
+		if err != nil {
+			t.Fatalf("signed delivery failed verification: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+}
+
+func TestWebhookGeneratedSecret(t *testing.T) {
+	testWebhookStart()
+
+	verified := make(chan error, 1)
+	verifier := make(chan *webhook.Verifier, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := (<-verifier).Verify(r)
+		verified <- err
+	}))
+	defer srv.Close()
+
+	jid := "webhook-generated-" + webhookID()
+
+	subscriber, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(subscriber.Secret) != 64 {
+		t.Fatalf("generated secret is %q", subscriber.Secret)
+	}
+
+	verifier <- webhook.NewVerifier(subscriber.Secret)
+
+	webhookNotify(Event{JID: jid, Type: EventState, Data: SessionInfo{State: StateConnected}, Time: time.Now()})
+
+	select {
+	case err := <-verified:
+		if err != nil {
+			t.Fatalf("delivery failed verification with the generated secret: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	// Registering The URL Again Without a Secret Replaces The Secret
+	replaced, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replaced.ID != subscriber.ID || len(replaced.Secret) != 64 || replaced.Secret == subscriber.Secret {
+		t.Errorf("registering again answered %+v after %+v", replaced, subscriber)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	testWebhookStart()
+
+	srv, requests := testWebhookServer(t, func(request int32) int {
+		if request < 3 {
+			return http.StatusInternalServerError
+		}
+
+		return http.StatusOK
+	})
+
+	jid := "webhook-retry-" + webhookID()
+
+	_, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookNotify(Event{JID: jid, Type: EventState, Data: SessionInfo{State: StateConnected}, Time: time.Now()})
+
+	testEventually(t, func() bool {
+		return atomic.LoadInt32(requests) == 3 && testWebhookPending(t, jid) == 0
+	})
+
+	failed, err := WAWebhookFailed(jid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failed) != 0 {
+		t.Fatalf("delivered webhook was dead-lettered: %+v", failed)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	testWebhookStart()
+
+	var healthy int32
+
+	srv, requests := testWebhookServer(t, func(request int32) int {
+		if atomic.LoadInt32(&healthy) == 0 {
+			return http.StatusServiceUnavailable
+		}
+
+		return http.StatusOK
+	})
+
+	jid := "webhook-deadletter-" + webhookID()
+
+	_, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookNotify(Event{JID: jid, Type: EventState, Data: SessionInfo{State: StateConnected}, Time: time.Now()})
+
+	var failed []WebhookDelivery
+	testEventually(t, func() bool {
+		failed, err = WAWebhookFailed(jid)
+		return err == nil && len(failed) == 1
+	})
+
+	if failed[0].Attempts != WebhookMaxAttempts || failed[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected dead letter: %+v", failed[0])
+	}
+
+	if pending := testWebhookPending(t, jid); pending != 0 {
+		t.Fatalf("dead-lettered delivery is still pending %d time(s)", pending)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+
+	_, err = WAWebhookReplay(jid, failed[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEventually(t, func() bool {
+		return atomic.LoadInt32(requests) == int32(WebhookMaxAttempts)+1 && testWebhookPending(t, jid) == 0
+	})
+
+	failed, err = WAWebhookFailed(jid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failed) != 0 {
+		t.Fatalf("replayed delivery is still dead-lettered: %+v", failed)
+	}
+
+	_, err = WAWebhookReplay(jid, "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("replaying a missing delivery answered %v", err)
+	}
+}
+
+func TestWebhookPendingResume(t *testing.T) {
+	testWebhookStart()
+
+	srv, requests := testWebhookServer(t, func(request int32) int {
+		return http.StatusOK
+	})
+
+	jid := "webhook-resume-" + webhookID()
+
+	subscriber, err := WAWebhookPut(jid, WebhookSubscriber{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Store a Delivery The Way a Previous Run Left it Between Two Attempts
+	now := time.Now()
+	delivery := WebhookDelivery{
+		ID:            webhookID(),
+		JID:           jid,
+		Subscriber:    subscriber.ID,
+		URL:           subscriber.URL,
+		Payload:       []byte(`{}`),
+		Attempts:      1,
+		NextAttemptAt: now.Add(-time.Second),
+		CreatedAt:     now.Add(-time.Minute),
+		UpdatedAt:     now.Add(-time.Minute),
+	}
+
+	err = svc.Store.Update(func(tx *bolt.Tx) error {
+		return webhookPendingPut(tx, delivery)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookWake()
+
+	testEventually(t, func() bool {
+		return atomic.LoadInt32(requests) == 1 && testWebhookPending(t, jid) == 0
+	})
+}
+
+func TestWebhookEnvelope(t *testing.T) {
+	testWebhookStart()
+
+	type received struct {
+		event string
+		body  map[string]interface{}
+	}
+
+	bodies := make(chan received, 2)
+
+	receiver := func() *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+
+			bodies <- received{event: r.Header.Get(webhook.HeaderEvent), body: body}
+		}))
+		t.Cleanup(srv.Close)
+
+		return srv
+	}
+
+	jid := "webhook-envelope-" + webhookID()
+
+	_, err := WAWebhookPut(jid, WebhookSubscriber{URL: receiver().URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = WAWebhookPut(jid, WebhookSubscriber{URL: receiver().URL, Envelope: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhookNotify(Event{ID: 7, JID: jid, Type: EventMessage, Data: WebhookMessage{JID: jid, Type: "text"}, Time: time.Now()})
+
+	envelopes := 0
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-bodies:
+			if got.event != "message.text" {
+				t.Fatalf("unexpected event header %q", got.event)
+			}
+
+			if _, ok := got.body["data"]; ok {
+				if got.body["type"] != EventMessage || got.body["id"] != float64(7) {
+					t.Fatalf("unexpected envelope %v", got.body)
+				}
+
+				envelopes++
+			} else if got.body["type"] != "text" || got.body["jid"] != jid {
+				t.Fatalf("unexpected message body %v", got.body)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook not delivered")
+		}
+	}
+
+	if envelopes != 1 {
+		t.Fatalf("%d of 2 webhooks got the envelope, want 1", envelopes)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	hlp "github.com/theveloped/go-whatsapp-rest/helper"
 	svc "github.com/theveloped/go-whatsapp-rest/service"
+	"github.com/theveloped/go-whatsapp-rest/wajid"
 )
 
 // Server Variable
@@ -20,15 +23,64 @@ func init() {
 	// Initialize Routes
 	routesInit()
 
+	// Initialize WhatsApp Reconnect Backoff
+	hlp.ReconnectDelayMin = time.Duration(svc.Config.GetInt("WHATSAPP_RECONNECT_DELAY_MIN")) * time.Second
+	hlp.ReconnectDelayMax = time.Duration(svc.Config.GetInt("WHATSAPP_RECONNECT_DELAY_MAX")) * time.Second
+
+	// Initialize WhatsApp Outbound Queue Retry Policy
+	hlp.QueueMaxAttempts = svc.Config.GetInt("WHATSAPP_QUEUE_MAX_ATTEMPTS")
+	hlp.QueueRetryDelay = time.Duration(svc.Config.GetInt("WHATSAPP_QUEUE_RETRY_DELAY")) * time.Second
+
+	// Initialize WhatsApp Quote Cache
+	hlp.QuoteCacheSize = svc.Config.GetInt("WHATSAPP_QUOTE_CACHE_SIZE")
+
+	// Initialize WhatsApp Webhook Delivery Policy
+	hlp.WebhookWorkers = svc.Config.GetInt("WHATSAPP_WEBHOOK_WORKERS")
+	hlp.WebhookMaxAttempts = svc.Config.GetInt("WHATSAPP_WEBHOOK_MAX_ATTEMPTS")
+	hlp.WebhookRetryDelay = time.Duration(svc.Config.GetInt("WHATSAPP_WEBHOOK_RETRY_DELAY")) * time.Second
+	hlp.WebhookTimeout = time.Duration(svc.Config.GetInt("WHATSAPP_WEBHOOK_TIMEOUT")) * time.Second
+
+	// Initialize WhatsApp Event Log
+	hlp.EventLogSize = svc.Config.GetInt("WHATSAPP_EVENT_LOG_SIZE")
+
+	// Initialize WhatsApp Login Timeout
+	hlp.LoginMaxDuration = time.Duration(svc.Config.GetInt("WHATSAPP_LOGIN_TIMEOUT")) * time.Second
+
+	// Initialize WhatsApp Recipient Default Country Code
+	wajid.DefaultCountryCode = svc.Config.GetString("WHATSAPP_DEFAULT_COUNTRY_CODE")
+
+	// Initialize WhatsApp Contact Check Cache
+	hlp.ContactCheckTTL = time.Duration(svc.Config.GetInt("WHATSAPP_CONTACT_CHECK_TTL")) * time.Second
+	hlp.ContactCheckTimeout = time.Duration(svc.Config.GetInt("WHATSAPP_CONTACT_CHECK_TIMEOUT")) * time.Second
+
+	// Initialize WhatsApp Contact and Chat Lists
+	hlp.RosterPersist = svc.Config.GetBool("WHATSAPP_ROSTER_PERSIST")
+
+	// Initialize WhatsApp Group Listing
+	hlp.GroupListTimeout = time.Duration(svc.Config.GetInt("WHATSAPP_GROUP_LIST_TIMEOUT")) * time.Second
+
 	// Initialize Server
 	svr = svc.NewServer(svc.Router)
 }
 
 // Main Function
 func main() {
+	// Indexing WhatsApp Message History Stored Before Searching Existed
+	hlp.WASearchReindex()
+
 	// Starting Server
 	svr.Start()
 
+	// Starting WhatsApp Outbound Queue
+	hlp.WAQueueStart()
+
+	// Starting WhatsApp Webhook Dispatcher
+	hlp.WAWebhookStart()
+
+	// Restoring Stored WhatsApp Sessions
+	go hlp.WASessionRestoreAll(svc.Config.GetString("SERVER_STORE_PATH"),
+		svc.Config.GetInt("SERVER_RESTORE_TIMEOUT"), svc.Config.GetInt("SERVER_RESTORE_CONCURRENCY"))
+
 	// Make Channel for OS Signal
 	sig := make(chan os.Signal, 1)
 
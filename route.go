@@ -3,6 +3,7 @@ package main
 import (
 	ctl "github.com/theveloped/go-whatsapp-rest/controller"
 	svc "github.com/theveloped/go-whatsapp-rest/service"
+
 	"github.com/go-chi/chi"
 )
 
@@ -11,19 +12,99 @@ func routesInit() {
 	// Set Endpoint for Root Functions
 	svc.Router.Get(svc.RouterBasePath, ctl.GetIndex)
 	svc.Router.Get(svc.RouterBasePath+"/health", ctl.GetHealth)
+	svc.Router.With(svc.AuthJWT, svc.AuthAdmin).Get(svc.RouterBasePath+"/health/sessions", ctl.GetHealthSessions)
 
 	// Set Endpoint for Authorization Functions
 	svc.Router.With(svc.AuthBasic).Get(svc.RouterBasePath+"/auth", ctl.GetAuth)
 
-	// Set Endpoint for WhatsApp Functions
-	svc.Router.With(svc.AuthJWT).Post(svc.RouterBasePath+"/login", ctl.WhatsAppLogin)
-	svc.Router.With(svc.AuthJWT).Post(svc.RouterBasePath+"/messagetext", ctl.WhatsAppSendText)
-	svc.Router.With(svc.AuthJWT).Post(svc.RouterBasePath+"/messageimage", ctl.WhatsAppSendImage)
-	svc.Router.With(svc.AuthJWT).Post(svc.RouterBasePath+"/logout", ctl.WhatsAppLogout)
+	// Set Endpoint for User Functions
+	svc.Router.Route(svc.RouterBasePath+"/users", func(r chi.Router) {
+		r.Use(svc.AuthJWT, svc.AuthAdmin)
+
+		r.Get("/", ctl.GetUsers)
+		r.Post("/", ctl.CreateUser)
+		r.Get("/{username}", ctl.GetUser)
+		r.Patch("/{username}", ctl.UpdateUser)
+		r.Delete("/{username}", ctl.DeleteUser)
+	})
+
+	// Set Endpoint for Account Functions
+	svc.Router.Route(svc.RouterBasePath+"/accounts", func(r chi.Router) {
+		r.With(svc.AuthJWT).Get("/", ctl.WhatsAppGetAccounts)
+		r.With(svc.AuthJWT).Post("/", ctl.WhatsAppCreateAccount)
+
+		r.Route("/{account}", func(r chi.Router) {
+			r.Use(svc.AuthJWT, ctl.AccountScope)
+
+			r.Get("/", ctl.WhatsAppGetAccount)
+			r.Delete("/", ctl.WhatsAppDeleteAccount)
+			r.Put("/users/{user}", ctl.WhatsAppGrantAccount)
+			r.Delete("/users/{user}", ctl.WhatsAppRevokeAccount)
+			r.Get("/session", ctl.WhatsAppGetSession)
+
+			routesAccount(r, "")
+		})
+	})
+
+	// Set Endpoint for WhatsApp Functions Acting on The Account Named After The User
+	svc.Router.Group(func(r chi.Router) {
+		r.Use(svc.AuthJWT, ctl.AccountScope)
+
+		r.Get(svc.RouterBasePath+"/sessions/{account}", ctl.WhatsAppGetSession)
+
+		routesAccount(r, svc.RouterBasePath)
+	})
+}
+
+// RoutesAccount Function Sets The Endpoints Acting on an Account Under a Base Path
+func routesAccount(r chi.Router, basePath string) {
+	r.Post(basePath+"/login", ctl.WhatsAppLogin)
+	r.Get(basePath+"/login/{loginID}", ctl.WhatsAppGetLogin)
+	r.Delete(basePath+"/login/{loginID}", ctl.WhatsAppCancelLogin)
+	r.Post(basePath+"/messagetext", ctl.WhatsAppSendMessage)
+	r.Post(basePath+"/messageimage", ctl.WhatsAppSendMedia)
+	r.Post(basePath+"/logout", ctl.WhatsAppLogout)
+	r.Get(basePath+"/events", ctl.WhatsAppGetEvents)
+	r.Get(basePath+"/search", ctl.WhatsAppSearch)
 
 	// Restful endpoints
-	svc.Router.Route(svc.RouterBasePath + "/messages", func(r chi.Router) {
-		r.With(svc.AuthJWT).Get("/{messageID}/data", ctl.WhatsAppGetAttachment)
-		r.With(svc.AuthJWT).Post("/", ctl.WhatsAppSendGeneric)
+	r.Route(basePath+"/messages", func(r chi.Router) {
+		r.Get("/{messageID}", ctl.WhatsAppGetMessage)
+		r.Get("/{messageID}/data", ctl.WhatsAppGetAttachment)
+		r.Post("/", ctl.WhatsAppSendGeneric)
+	})
+
+	r.Route(basePath+"/scheduled", func(r chi.Router) {
+		r.Get("/", ctl.WhatsAppGetScheduled)
+		r.Delete("/{messageID}", ctl.WhatsAppCancelScheduled)
+	})
+
+	r.Route(basePath+"/contacts", func(r chi.Router) {
+		r.Get("/", ctl.WhatsAppGetContacts)
+		r.Post("/check", ctl.WhatsAppCheckContacts)
+	})
+
+	r.Route(basePath+"/chats", func(r chi.Router) {
+		r.Get("/", ctl.WhatsAppGetChats)
+		r.Get("/{chatID}/messages", ctl.WhatsAppGetChatMessages)
+	})
+
+	r.Route(basePath+"/groups", func(r chi.Router) {
+		r.Get("/", ctl.WhatsAppGetGroups)
+		r.Post("/", ctl.WhatsAppCreateGroup)
+		r.Get("/{groupID}", ctl.WhatsAppGetGroup)
+		r.Patch("/{groupID}", ctl.WhatsAppUpdateGroup)
+		r.Delete("/{groupID}", ctl.WhatsAppLeaveGroup)
+		r.Post("/{groupID}/participants/{action}", ctl.WhatsAppUpdateGroupParticipants)
+		r.Get("/{groupID}/invite", ctl.WhatsAppGetGroupInvite)
+	})
+
+	r.Route(basePath+"/webhooks", func(r chi.Router) {
+		r.Get("/", ctl.WhatsAppGetWebhooks)
+		r.Put("/", ctl.WhatsAppPutWebhook)
+		r.Delete("/", ctl.WhatsAppDeleteWebhook)
+		r.Delete("/{webhookID}", ctl.WhatsAppDeleteWebhook)
+		r.Get("/failed", ctl.WhatsAppGetWebhookFailed)
+		r.Post("/failed/{deliveryID}/replay", ctl.WhatsAppReplayWebhookFailed)
 	})
 }
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -48,11 +50,18 @@ func AuthBasic(next http.Handler) http.Handler {
 			return
 		}
 
-		// Make Credentials to JSON Format
-		jsonCredentials := `{"username": "` + authCredentials[0] + `", "password": "` + authCredentials[1] + `"}`
+		// Make Credentials to JSON Format, Escaping Quotes and Backslashes
+		jsonCredentials, err := json.Marshal(ReqGetBasic{
+			Username: authCredentials[0],
+			Password: authCredentials[1],
+		})
+		if err != nil {
+			ResponseInternalError(w, err.Error())
+			return
+		}
 
 		// Rewrite Body Content With Credentials in JSON Format
-		r.Body = ioutil.NopCloser(strings.NewReader(jsonCredentials))
+		r.Body = ioutil.NopCloser(bytes.NewReader(jsonCredentials))
 
 		// Call Next Handler Function With Current Request
 		next.ServeHTTP(w, r)
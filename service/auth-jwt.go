@@ -20,7 +20,8 @@ type ResGetJWT struct {
 
 // JWT Claims Data Struct
 type jwtClaimsData struct {
-	Data string `json:"data"`
+	Data    string `json:"data"`
+	Created string `json:"created"`
 	jwt.StandardClaims
 }
 
@@ -54,8 +55,20 @@ func AuthJWT(next http.Handler) http.Handler {
 			return
 		}
 
+		// Tokens of Users Deleted or Disabled Since They Were Issued Are Refused,
+		// so Are Tokens of an Earlier User of The Same Name
+		username, _ := authClaims["data"].(string)
+		created, _ := authClaims["created"].(string)
+
+		user, err := UserGet(username)
+		if err != nil || user.Disabled || created != user.CreatedAt.Format(time.RFC3339Nano) {
+			Log("warn", "http-access", "unauthorized user "+username+" method "+r.Method+" at URI "+r.RequestURI)
+			ResponseUnauthorized(w)
+			return
+		}
+
 		// Encrypt Claims Using RSA Encryption
-		claimsEncrypted, err := EncryptWithRSA(authClaims["data"].(string))
+		claimsEncrypted, err := EncryptWithRSA(username)
 		if err != nil {
 			ResponseInternalError(w, err.Error())
 			return
@@ -69,8 +82,8 @@ func AuthJWT(next http.Handler) http.Handler {
 	})
 }
 
-// GetJWTToken Function to Generate JWT Token
-func GetJWTToken(payload interface{}) (string, error) {
+// GetJWTToken Function to Generate JWT Token of a User
+func GetJWTToken(user User) (string, error) {
 	// Convert Signing Key in Byte Format
 	signingKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyRSACfg.BytePrivate)
 	if err != nil {
@@ -79,7 +92,8 @@ func GetJWTToken(payload interface{}) (string, error) {
 
 	// Create JWT Token With RS256 Method And Set JWT Claims
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwtClaimsData{
-		payload.(string),
+		user.Username,
+		user.CreatedAt.Format(time.RFC3339Nano),
 		jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
 		},
@@ -0,0 +1,430 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User Roles
+const (
+	UserRoleAdmin = "admin"
+	UserRoleUser  = "user"
+)
+
+// Password Length Bounds
+const (
+	userPasswordMin = 8
+	userPasswordMax = 72
+)
+
+// Store Bucket Keeping API Users by Username
+const bucketUsers = "users"
+
+// userNamePattern Restricts Usernames to Names Safe For Session Files,
+// as Users Act on The Account Named After Them by Default
+var userNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// userDummyHash is Compared Against For Unknown Users so They Take as
+// Long to Reject as Wrong Passwords
+var userDummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+// User Errors
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUserExists       = errors.New("user already exists")
+	ErrUserInvalid      = errors.New("invalid user")
+	ErrUserLastAdmin    = errors.New("the last enabled admin can not be removed, disabled or demoted")
+	ErrUserUnauthorized = errors.New("invalid authorization")
+)
+
+// User Struct Describing an API User
+type User struct {
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UserUpdate Struct Describing The Changes to a User, Nil Fields Are Kept
+type UserUpdate struct {
+	Password *string
+	Role     *string
+	Disabled *bool
+}
+
+// userStored Struct is a User as Kept in The Store With its Password Hash
+type userStored struct {
+	User
+	PasswordHash string `json:"password_hash"`
+}
+
+// IsAdmin Method Reports Whether The User is an Enabled Admin
+func (u User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin && !u.Disabled
+}
+
+// UserInit Function Creates The Admin User From AUTH_USERNAME and
+// AUTH_PASSWORD The First Time The Store Has no Users
+func userInit() {
+	var empty bool
+
+	err := Store.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketUsers))
+		empty = bucket == nil || bucket.Stats().KeyN == 0
+
+		return nil
+	})
+	if err != nil {
+		Log("fatal", "init-user", err.Error())
+	}
+
+	if !empty {
+		return
+	}
+
+	username := Config.GetString("AUTH_USERNAME")
+	password := Config.GetString("AUTH_PASSWORD")
+
+	if len(password) == 0 {
+		Log("warn", "init-user", "no users exist and AUTH_PASSWORD is not set, nobody can log in")
+		return
+	}
+
+	_, err = UserCreate(username, password, UserRoleAdmin)
+	if err != nil {
+		Log("fatal", "init-user", err.Error())
+	}
+
+	Log("info", "init-user", "created admin user "+username)
+}
+
+// UserAuthenticate Function Returns The User Matching a Username and
+// Password, Failing Alike For Unknown, Disabled or Wrong Credentials
+func UserAuthenticate(username string, password string) (User, error) {
+	stored, found, err := userGet(username)
+	if err != nil {
+		return User{}, err
+	}
+
+	hash := userDummyHash
+	if found {
+		hash = []byte(stored.PasswordHash)
+	}
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || !found || stored.Disabled {
+		return User{}, ErrUserUnauthorized
+	}
+
+	return stored.User, nil
+}
+
+// UserGet Function Returns a User
+func UserGet(username string) (User, error) {
+	stored, found, err := userGet(username)
+	if err != nil {
+		return User{}, err
+	}
+
+	if !found {
+		return User{}, ErrUserNotFound
+	}
+
+	return stored.User, nil
+}
+
+// UserList Function Returns Every User Sorted by Username
+func UserList() ([]User, error) {
+	users := make([]User, 0)
+
+	err := Store.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketUsers))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var stored userStored
+
+			err := json.Unmarshal(v, &stored)
+			if err != nil {
+				return err
+			}
+
+			users = append(users, stored.User)
+			return nil
+		})
+	})
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return users, err
+}
+
+// UserCreate Function Creates a User With a Password and a Role
+func UserCreate(username string, password string, role string) (User, error) {
+	if !userNamePattern.MatchString(username) {
+		return User{}, fmt.Errorf("%w: username must be up to 64 letters, digits, dots, dashes or underscores", ErrUserInvalid)
+	}
+
+	if len(role) == 0 {
+		role = UserRoleUser
+	}
+
+	err := userValidateRole(role)
+	if err != nil {
+		return User{}, err
+	}
+
+	err = userValidatePassword(password)
+	if err != nil {
+		return User{}, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, err
+	}
+
+	now := time.Now()
+
+	stored := userStored{
+		User: User{
+			Username:  username,
+			Role:      role,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		PasswordHash: string(hash),
+	}
+
+	err = Store.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(bucketUsers))
+		if err != nil {
+			return err
+		}
+
+		if bucket.Get([]byte(username)) != nil {
+			return ErrUserExists
+		}
+
+		return userPut(bucket, stored)
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return stored.User, nil
+}
+
+// UserChange Function Changes The Password, Role or Disabled Flag of a User
+func UserChange(username string, update UserUpdate) (User, error) {
+	var hash []byte
+	var err error
+
+	if update.Password != nil {
+		err = userValidatePassword(*update.Password)
+		if err != nil {
+			return User{}, err
+		}
+
+		hash, err = bcrypt.GenerateFromPassword([]byte(*update.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return User{}, err
+		}
+	}
+
+	if update.Role != nil {
+		err = userValidateRole(*update.Role)
+		if err != nil {
+			return User{}, err
+		}
+	}
+
+	var stored userStored
+
+	err = Store.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketUsers))
+		if bucket == nil {
+			return ErrUserNotFound
+		}
+
+		data := bucket.Get([]byte(username))
+		if data == nil {
+			return ErrUserNotFound
+		}
+
+		err := json.Unmarshal(data, &stored)
+		if err != nil {
+			return err
+		}
+
+		wasAdmin := stored.IsAdmin()
+
+		if hash != nil {
+			stored.PasswordHash = string(hash)
+		}
+
+		if update.Role != nil {
+			stored.Role = *update.Role
+		}
+
+		if update.Disabled != nil {
+			stored.Disabled = *update.Disabled
+		}
+
+		if wasAdmin && !stored.IsAdmin() {
+			err = userKeepAdmin(bucket, username)
+			if err != nil {
+				return err
+			}
+		}
+
+		stored.UpdatedAt = time.Now()
+
+		return userPut(bucket, stored)
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return stored.User, nil
+}
+
+// UserDelete Function Deletes a User
+func UserDelete(username string) error {
+	return Store.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketUsers))
+		if bucket == nil {
+			return ErrUserNotFound
+		}
+
+		data := bucket.Get([]byte(username))
+		if data == nil {
+			return ErrUserNotFound
+		}
+
+		var stored userStored
+
+		err := json.Unmarshal(data, &stored)
+		if err != nil {
+			return err
+		}
+
+		if stored.IsAdmin() {
+			err = userKeepAdmin(bucket, username)
+			if err != nil {
+				return err
+			}
+		}
+
+		return bucket.Delete([]byte(username))
+	})
+}
+
+// AuthAdmin Function as Midleware Allowing Only Admin Users, Must Follow AuthJWT
+func AuthAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, err := GetJWTClaims(r.Header.Get("X-JWT-Claims"))
+		if err != nil {
+			ResponseInternalError(w, err.Error())
+			return
+		}
+
+		user, err := UserGet(username)
+		if err != nil || !user.IsAdmin() {
+			Log("warn", "http-access", "forbidden method "+r.Method+" at URI "+r.RequestURI+" for user "+username)
+			ResponseForbidden(w, "")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// userGet Function Reads a User, Reporting Whether it Exists
+func userGet(username string) (userStored, bool, error) {
+	var stored userStored
+	var found bool
+
+	err := Store.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketUsers))
+		if bucket == nil {
+			return nil
+		}
+
+		data := bucket.Get([]byte(username))
+		if data == nil {
+			return nil
+		}
+
+		found = true
+
+		return json.Unmarshal(data, &stored)
+	})
+
+	return stored, found, err
+}
+
+// userValidatePassword Function Checks The Length of a Password, Bounded
+// Above by The Bytes bcrypt Takes Into Account
+func userValidatePassword(password string) error {
+	if len(password) < userPasswordMin || len(password) > userPasswordMax {
+		return fmt.Errorf("%w: password must be between %d and %d characters", ErrUserInvalid, userPasswordMin, userPasswordMax)
+	}
+
+	return nil
+}
+
+// userValidateRole Function Checks a Role is Known
+func userValidateRole(role string) error {
+	if role != UserRoleAdmin && role != UserRoleUser {
+		return fmt.Errorf("%w: role must be %s or %s", ErrUserInvalid, UserRoleAdmin, UserRoleUser)
+	}
+
+	return nil
+}
+
+// userKeepAdmin Function Fails Unless an Enabled Admin Other Than a User Remains
+func userKeepAdmin(bucket *bolt.Bucket, username string) error {
+	c := bucket.Cursor()
+
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if string(k) == username {
+			continue
+		}
+
+		var stored userStored
+
+		err := json.Unmarshal(v, &stored)
+		if err != nil {
+			return err
+		}
+
+		if stored.IsAdmin() {
+			return nil
+		}
+	}
+
+	return ErrUserLastAdmin
+}
+
+// userPut Function Writes a User
+func userPut(bucket *bolt.Bucket, stored userStored) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(stored.Username), data)
+}
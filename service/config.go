@@ -80,6 +80,15 @@ func configLoadValues() {
 	// Server Store Path Value
 	Config.SetDefault("SERVER_STORE_PATH", "./stores")
 
+	// Server Store Database Value
+	Config.SetDefault("SERVER_STORE_DB", "whatsapp.db")
+
+	// Server Restore Timeout Value
+	Config.SetDefault("SERVER_RESTORE_TIMEOUT", 10)
+
+	// Server Restore Concurrency Value
+	Config.SetDefault("SERVER_RESTORE_CONCURRENCY", 4)
+
 	// Server Upload Path Value
 	Config.SetDefault("SERVER_UPLOAD_PATH", "./uploads")
 
@@ -103,14 +112,65 @@ func configLoadValues() {
 	Config.SetDefault("CORS_ALLOWED_HEADER", "Origin, X-Requested-With, Content-Type, Accept, Authorization")
 	routerCORSCfg.Headers = Config.GetString("CORS_ALLOWED_HEADER")
 
+	// WhatsApp Reconnect Minimum Delay Value in Seconds
+	Config.SetDefault("WHATSAPP_RECONNECT_DELAY_MIN", 1)
+
+	// WhatsApp Reconnect Maximum Delay Value in Seconds
+	Config.SetDefault("WHATSAPP_RECONNECT_DELAY_MAX", 300)
+
+	// WhatsApp Queue Maximum Attempts Value
+	Config.SetDefault("WHATSAPP_QUEUE_MAX_ATTEMPTS", 5)
+
+	// WhatsApp Queue Retry Delay Value in Seconds
+	Config.SetDefault("WHATSAPP_QUEUE_RETRY_DELAY", 5)
+
+	// WhatsApp Quote Cache Size Value
+	Config.SetDefault("WHATSAPP_QUOTE_CACHE_SIZE", 1000)
+
+	// WhatsApp Webhook Workers Value
+	Config.SetDefault("WHATSAPP_WEBHOOK_WORKERS", 4)
+
+	// WhatsApp Webhook Maximum Attempts Value
+	Config.SetDefault("WHATSAPP_WEBHOOK_MAX_ATTEMPTS", 5)
+
+	// WhatsApp Webhook Retry Delay Value in Seconds
+	Config.SetDefault("WHATSAPP_WEBHOOK_RETRY_DELAY", 5)
+
+	// WhatsApp Webhook Request Timeout Value in Seconds
+	Config.SetDefault("WHATSAPP_WEBHOOK_TIMEOUT", 10)
+
+	// WhatsApp Event Log Size Value
+	Config.SetDefault("WHATSAPP_EVENT_LOG_SIZE", 1000)
+
+	// WhatsApp Login Timeout Value in Seconds
+	Config.SetDefault("WHATSAPP_LOGIN_TIMEOUT", 300)
+
+	// WhatsApp Default Country Code Value
+	Config.SetDefault("WHATSAPP_DEFAULT_COUNTRY_CODE", "")
+
+	// WhatsApp Contact Check Cache TTL Value in Seconds
+	Config.SetDefault("WHATSAPP_CONTACT_CHECK_TTL", 86400)
+
+	// WhatsApp Contact Check Request Timeout Value in Seconds
+	Config.SetDefault("WHATSAPP_CONTACT_CHECK_TIMEOUT", 30)
+
+	// WhatsApp Contact and Chat List Persistence Value
+	Config.SetDefault("WHATSAPP_ROSTER_PERSIST", false)
+
+	// WhatsApp Group List Timeout Value per Group in Seconds
+	Config.SetDefault("WHATSAPP_GROUP_LIST_TIMEOUT", 5)
+
 	// Crypt RSA Private Key File Value
 	Config.SetDefault("CRYPT_PRIVATE_KEY_FILE", "./private.key")
 
 	// Crypt RSA Public Key File Value
 	Config.SetDefault("CRYPT_PUBLIC_KEY_FILE", "./public.key")
 
-	// Crypt admin password
-	Config.SetDefault("AUTH_PASSWORD", "83e4060e-78e1-4fe5-9977-aeeccd46a2b8")
+	// Admin Username Created With AUTH_PASSWORD While There Are no Users
+	Config.SetDefault("AUTH_USERNAME", "admin")
+
+	// Admin Password, Only Used to Create The First Admin User
+	Config.SetDefault("AUTH_PASSWORD", "")
 
 	// Crypt admin password
 	Config.SetDefault("DIALOGFLOW_CREDENTIALS_PATH", "./configs/dialogflow-credentials.json")
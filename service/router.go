@@ -17,10 +17,11 @@ type ResSuccess struct {
 
 // ResError Struct
 type ResError struct {
-	Status  bool   `json:"status"`
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Error   string `json:"error"`
+	Status    bool   `json:"status"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Error     string `json:"error"`
+	ErrorCode string `json:"error_code"`
 }
 
 // Router CORS Configuration Struct
@@ -190,6 +191,27 @@ func ResponseNotFound(w http.ResponseWriter, message string) {
 	response.Code = http.StatusNotFound
 	response.Message = "Not Found"
 	response.Error = message
+	response.ErrorCode = "not_found"
+
+	// Set Response Data to HTTP
+	ResponseWrite(w, response.Code, response)
+}
+
+// ResponseForbidden Function
+func ResponseForbidden(w http.ResponseWriter, message string) {
+	var response ResError
+
+	// Set Default Message
+	if len(message) == 0 {
+		message = "Forbidden"
+	}
+
+	// Set Response Data
+	response.Status = false
+	response.Code = http.StatusForbidden
+	response.Message = "Forbidden"
+	response.Error = message
+	response.ErrorCode = "forbidden"
 
 	// Set Response Data to HTTP
 	ResponseWrite(w, response.Code, response)
@@ -209,6 +231,7 @@ func ResponseMethodNotAllowed(w http.ResponseWriter, message string) {
 	response.Code = http.StatusMethodNotAllowed
 	response.Message = "Method Not Allowed"
 	response.Error = message
+	response.ErrorCode = "method_not_allowed"
 
 	// Set Response Data to HTTP
 	ResponseWrite(w, response.Code, response)
@@ -228,6 +251,7 @@ func ResponseBadRequest(w http.ResponseWriter, message string) {
 	response.Code = http.StatusBadRequest
 	response.Message = "Bad Request"
 	response.Error = message
+	response.ErrorCode = "bad_request"
 
 	// Logging Error
 	Log("error", "http-access", strings.ToLower(message))
@@ -250,6 +274,30 @@ func ResponseInternalError(w http.ResponseWriter, message string) {
 	response.Code = http.StatusInternalServerError
 	response.Message = "Internal Server Error"
 	response.Error = message
+	response.ErrorCode = "internal_error"
+
+	// Logging Error
+	Log("error", "http-access", strings.ToLower(message))
+
+	// Set Response Data to HTTP
+	ResponseWrite(w, response.Code, response)
+}
+
+// ResponseError Function
+func ResponseError(w http.ResponseWriter, code int, errorCode string, message string) {
+	var response ResError
+
+	// Set Default Message
+	if len(message) == 0 {
+		message = http.StatusText(code)
+	}
+
+	// Set Response Data
+	response.Status = false
+	response.Code = code
+	response.Message = http.StatusText(code)
+	response.Error = message
+	response.ErrorCode = errorCode
 
 	// Logging Error
 	Log("error", "http-access", strings.ToLower(message))
@@ -267,6 +315,7 @@ func ResponseUnauthorized(w http.ResponseWriter) {
 	response.Code = http.StatusUnauthorized
 	response.Message = "Unauthorized"
 	response.Error = "Unaothorized"
+	response.ErrorCode = "unauthorized"
 
 	// Set Response Data to HTTP
 	ResponseWrite(w, response.Code, response)
@@ -11,6 +11,12 @@ func Initialize() {
 	// Initialize Cryptography
 	cryptInit()
 
+	// Initialize Store
+	storeInit()
+
+	// Initialize Users
+	userInit()
+
 	// Initialize Router
 	routerInit()
 }
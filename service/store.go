@@ -0,0 +1,29 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store Variable
+var Store *bolt.DB
+
+// StoreInit Function
+func storeInit() {
+	var err error
+
+	// Make Sure Store Path is Exist
+	err = os.MkdirAll(Config.GetString("SERVER_STORE_PATH"), 0755)
+	if err != nil {
+		Log("fatal", "init-store", err.Error())
+	}
+
+	// Open Embedded Database File Inside Store Path
+	Store, err = bolt.Open(filepath.Join(Config.GetString("SERVER_STORE_PATH"), Config.GetString("SERVER_STORE_DB")), 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		Log("fatal", "init-store", err.Error())
+	}
+}
@@ -0,0 +1,487 @@
+// Package wafake Provides a Scriptable In-Memory WhatsApp Client For Tests,
+// Standing in For The Rhymen Connection Behind helper.Client
+package wafake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	whatsapp "github.com/Rhymen/go-whatsapp"
+)
+
+// Client Struct as Scriptable In-Memory WhatsApp Client
+// Every Func Field is Optional, When It is Nil The Default Behaviour is Used
+type Client struct {
+	QRCode  string
+	Session whatsapp.Session
+
+	LoginFunc         func(qr chan<- string) (whatsapp.Session, error)
+	RestoreFunc       func(session whatsapp.Session) (whatsapp.Session, error)
+	SendFunc          func(message interface{}) (string, error)
+	ExistFunc         func(jid string) (bool, error)
+	LogoutFunc        func() error
+	GroupMetaDataFunc func(jid string) (<-chan string, error)
+
+	mu          sync.Mutex
+	loggedIn    bool
+	dropped     bool
+	sequence    int
+	disconnects int
+	sent        []interface{}
+	presences   []string
+	handlers    []whatsapp.Handler
+	groups      map[string]*fakeGroup
+}
+
+// fakeGroup Struct Describing a Group Kept by The Client
+type fakeGroup struct {
+	subject      string
+	participants map[string]bool
+	created      int64
+}
+
+// NewClient Function to Create a Client
+func NewClient() *Client {
+	return &Client{
+		QRCode: "fake-qr-code",
+		Session: whatsapp.Session{
+			ClientId: "fake-client-id",
+			Wid:      "fake@s.whatsapp.net",
+		},
+	}
+}
+
+// Login Method Delivers QRCode and Returns Session
+func (c *Client) Login(qr chan<- string) (whatsapp.Session, error) {
+	if c.LoginFunc != nil {
+		session, err := c.LoginFunc(qr)
+		if err == nil {
+			c.setLoggedIn(true)
+		}
+
+		return session, err
+	}
+
+	if c.isLoggedIn() {
+		return whatsapp.Session{}, whatsapp.ErrAlreadyLoggedIn
+	}
+
+	qr <- c.QRCode
+	c.setLoggedIn(true)
+
+	return c.Session, nil
+}
+
+// RestoreWithSession Method Accepts Any Session by Default
+func (c *Client) RestoreWithSession(session whatsapp.Session) (whatsapp.Session, error) {
+	if c.RestoreFunc != nil {
+		session, err := c.RestoreFunc(session)
+		if err == nil {
+			c.setLoggedIn(true)
+			c.setDropped(false)
+		}
+
+		return session, err
+	}
+
+	if c.isLoggedIn() {
+		return whatsapp.Session{}, whatsapp.ErrAlreadyLoggedIn
+	}
+
+	c.setLoggedIn(true)
+	c.setDropped(false)
+
+	return session, nil
+}
+
+// Send Method Records The Message and Returns a Sequential Message ID
+// After Drop it Fails The Same Way a Closed Websocket Does
+func (c *Client) Send(message interface{}) (string, error) {
+	if c.isDropped() {
+		return "ERROR", errors.New("could not send proto: failed to write message: error writing to websocket: websocket: close sent")
+	}
+
+	if c.SendFunc != nil {
+		id, err := c.SendFunc(message)
+		if err == nil {
+			c.record(message)
+		}
+
+		return id, err
+	}
+
+	return c.record(message), nil
+}
+
+// Presence Method Records The Presence Update
+func (c *Client) Presence(jid string, presence whatsapp.Presence) (<-chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.presences = append(c.presences, jid+" "+string(presence))
+
+	ch := make(chan string, 1)
+	ch <- `{"status":200}`
+
+	return ch, nil
+}
+
+// Exist Method Answers Like WhatsApp Does For a Registered Number by Default
+func (c *Client) Exist(jid string) (<-chan string, error) {
+	exists := true
+
+	if c.ExistFunc != nil {
+		var err error
+
+		exists, err = c.ExistFunc(jid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ch := make(chan string, 1)
+	if exists {
+		ch <- fmt.Sprintf(`{"status":200,"jid":%q}`, jid)
+	} else {
+		ch <- `{"status":404}`
+	}
+
+	return ch, nil
+}
+
+// CreateGroup Method Creates a Group Owned by The Fake Account
+func (c *Client) CreateGroup(subject string, participants []string) (<-chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.groups == nil {
+		c.groups = make(map[string]*fakeGroup)
+	}
+
+	c.sequence++
+	created := int64(1500000000 + c.sequence)
+	gid := fmt.Sprintf("6280000000000-%d@g.us", created)
+
+	group := &fakeGroup{subject: subject, participants: map[string]bool{c.Session.Wid: true}, created: created}
+	for _, participant := range participants {
+		group.participants[participant] = false
+	}
+	c.groups[gid] = group
+
+	return fakeAnswer(map[string]interface{}{"status": 200, "gid": gid, "participants": fakeParticipants(participants)}), nil
+}
+
+// UpdateGroupSubject Method Renames a Group
+func (c *Client) UpdateGroupSubject(subject string, jid string) (<-chan string, error) {
+	return c.groupUpdate(jid, nil, func(group *fakeGroup, _ string) {
+		group.subject = subject
+	})
+}
+
+// AddMember Method Adds Participants to a Group
+func (c *Client) AddMember(jid string, participants []string) (<-chan string, error) {
+	return c.groupUpdate(jid, participants, func(group *fakeGroup, participant string) {
+		group.participants[participant] = false
+	})
+}
+
+// RemoveMember Method Removes Participants From a Group
+func (c *Client) RemoveMember(jid string, participants []string) (<-chan string, error) {
+	return c.groupUpdate(jid, participants, func(group *fakeGroup, participant string) {
+		delete(group.participants, participant)
+	})
+}
+
+// SetAdmin Method Promotes Participants of a Group
+func (c *Client) SetAdmin(jid string, participants []string) (<-chan string, error) {
+	return c.groupUpdate(jid, participants, func(group *fakeGroup, participant string) {
+		group.participants[participant] = true
+	})
+}
+
+// RemoveAdmin Method Demotes Participants of a Group
+func (c *Client) RemoveAdmin(jid string, participants []string) (<-chan string, error) {
+	return c.groupUpdate(jid, participants, func(group *fakeGroup, participant string) {
+		group.participants[participant] = false
+	})
+}
+
+// GetGroupMetaData Method Describes a Group Like WhatsApp Does
+func (c *Client) GetGroupMetaData(jid string) (<-chan string, error) {
+	if c.GroupMetaDataFunc != nil {
+		return c.GroupMetaDataFunc(jid)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group, ok := c.groups[jid]
+	if !ok {
+		return fakeAnswer(map[string]interface{}{"status": 404}), nil
+	}
+
+	participants := make([]map[string]interface{}, 0, len(group.participants))
+	for participant, admin := range group.participants {
+		participants = append(participants, map[string]interface{}{
+			"id":           strings.Replace(participant, "@s.whatsapp.net", "@c.us", 1),
+			"isAdmin":      admin,
+			"isSuperAdmin": participant == c.Session.Wid,
+		})
+	}
+
+	return fakeAnswer(map[string]interface{}{
+		"id":           jid,
+		"owner":        strings.Replace(c.Session.Wid, "@s.whatsapp.net", "@c.us", 1),
+		"subject":      group.subject,
+		"creation":     group.created,
+		"participants": participants,
+	}), nil
+}
+
+// GroupInviteLink Method Returns an Invite Code of a Group
+func (c *Client) GroupInviteLink(jid string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.groups[jid]; !ok {
+		return "", errors.New("request responded with 404")
+	}
+
+	return "FAKE" + strings.Split(jid, "@")[0], nil
+}
+
+// LeaveGroup Method Leaves a Group, Forgetting it
+func (c *Client) LeaveGroup(jid string) (<-chan string, error) {
+	return c.groupUpdate(jid, nil, func(group *fakeGroup, _ string) {
+		delete(c.groups, jid)
+	})
+}
+
+// groupUpdate Method Applies a Change to a Group For Every Participant,
+// Once For The Group Itself When There Are None
+func (c *Client) groupUpdate(jid string, participants []string, update func(*fakeGroup, string)) (<-chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group, ok := c.groups[jid]
+	if !ok {
+		return fakeAnswer(map[string]interface{}{"status": 404}), nil
+	}
+
+	if len(participants) == 0 {
+		update(group, "")
+		return fakeAnswer(map[string]interface{}{"status": 200}), nil
+	}
+
+	for _, participant := range participants {
+		update(group, participant)
+	}
+
+	return fakeAnswer(map[string]interface{}{"status": 200, "participants": fakeParticipants(participants)}), nil
+}
+
+// fakeParticipants Function Reports Every Participant as Succeeded
+func fakeParticipants(participants []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(participants))
+	for _, participant := range participants {
+		result = append(result, map[string]interface{}{
+			strings.Replace(participant, "@s.whatsapp.net", "@c.us", 1): map[string]string{"code": "200"},
+		})
+	}
+
+	return result
+}
+
+// fakeAnswer Function Returns a Channel Answering With a JSON Value
+func fakeAnswer(answer interface{}) <-chan string {
+	data, _ := json.Marshal(answer)
+
+	ch := make(chan string, 1)
+	ch <- string(data)
+
+	return ch
+}
+
+// Logout Method Ends The Fake Session
+func (c *Client) Logout() error {
+	if c.LogoutFunc != nil {
+		err := c.LogoutFunc()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.setLoggedIn(false)
+
+	return nil
+}
+
+// Disconnect Method Closes The Fake Connection, Sends Fail Afterwards Like After Drop
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	c.disconnects++
+	c.mu.Unlock()
+
+	c.setDropped(true)
+
+	return nil
+}
+
+// Disconnects Method Returns How Often Disconnect Was Called
+func (c *Client) Disconnects() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.disconnects
+}
+
+// AddHandler Method Registers a Handler
+func (c *Client) AddHandler(handler whatsapp.Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers = append(c.handlers, handler)
+}
+
+// RemoveHandlers Method Unregisters Every Handler
+func (c *Client) RemoveHandlers() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers = nil
+}
+
+// Emit Method Dispatches an Incoming Message to The Registered Handlers
+// The Message Can Be Any whatsapp Message Type, a Raw JSON String or an Error
+func (c *Client) Emit(message interface{}) {
+	c.mu.Lock()
+	handlers := append([]whatsapp.Handler(nil), c.handlers...)
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		switch m := message.(type) {
+		case error:
+			handler.HandleError(m)
+		case whatsapp.TextMessage:
+			if h, ok := handler.(whatsapp.TextMessageHandler); ok {
+				h.HandleTextMessage(m)
+			}
+		case whatsapp.ImageMessage:
+			if h, ok := handler.(whatsapp.ImageMessageHandler); ok {
+				h.HandleImageMessage(m)
+			}
+		case whatsapp.VideoMessage:
+			if h, ok := handler.(whatsapp.VideoMessageHandler); ok {
+				h.HandleVideoMessage(m)
+			}
+		case whatsapp.AudioMessage:
+			if h, ok := handler.(whatsapp.AudioMessageHandler); ok {
+				h.HandleAudioMessage(m)
+			}
+		case whatsapp.DocumentMessage:
+			if h, ok := handler.(whatsapp.DocumentMessageHandler); ok {
+				h.HandleDocumentMessage(m)
+			}
+		case whatsapp.LocationMessage:
+			if h, ok := handler.(whatsapp.LocationMessageHandler); ok {
+				h.HandleLocationMessage(m)
+			}
+		case whatsapp.LiveLocationMessage:
+			if h, ok := handler.(whatsapp.LiveLocationMessageHandler); ok {
+				h.HandleLiveLocationMessage(m)
+			}
+		case whatsapp.StickerMessage:
+			if h, ok := handler.(whatsapp.StickerMessageHandler); ok {
+				h.HandleStickerMessage(m)
+			}
+		case whatsapp.ContactMessage:
+			if h, ok := handler.(whatsapp.ContactMessageHandler); ok {
+				h.HandleContactMessage(m)
+			}
+		case string:
+			if h, ok := handler.(whatsapp.JsonMessageHandler); ok {
+				h.HandleJsonMessage(m)
+			}
+		case []whatsapp.Chat:
+			if h, ok := handler.(whatsapp.ChatListHandler); ok {
+				h.HandleChatList(m)
+			}
+		case []whatsapp.Contact:
+			if h, ok := handler.(whatsapp.ContactListHandler); ok {
+				h.HandleContactList(m)
+			}
+		case whatsapp.Contact:
+			if h, ok := handler.(whatsapp.NewContactHandler); ok {
+				h.HandleNewContact(m)
+			}
+		}
+	}
+}
+
+// Drop Method Closes The Fake Websocket, Failing Every Send Until
+// The Session is Restored and Reporting The Closure to The Handlers
+func (c *Client) Drop() {
+	c.mu.Lock()
+	c.loggedIn = false
+	c.dropped = true
+	c.mu.Unlock()
+
+	c.Emit(&whatsapp.ErrConnectionClosed{Code: 1006, Text: "fake connection dropped"})
+}
+
+// Sent Method Returns Every Message Sent Through The Client
+func (c *Client) Sent() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]interface{}(nil), c.sent...)
+}
+
+// Presences Method Returns Every Presence Update as "<jid> <presence>"
+func (c *Client) Presences() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.presences...)
+}
+
+func (c *Client) record(message interface{}) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sequence++
+	c.sent = append(c.sent, message)
+
+	return fmt.Sprintf("FAKE%016d", c.sequence)
+}
+
+func (c *Client) isLoggedIn() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.loggedIn
+}
+
+func (c *Client) setLoggedIn(loggedIn bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loggedIn = loggedIn
+}
+
+func (c *Client) isDropped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.dropped
+}
+
+func (c *Client) setDropped(dropped bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dropped = dropped
+}
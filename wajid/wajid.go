@@ -0,0 +1,213 @@
+// Package wajid Parses and Normalizes WhatsApp Recipients, Turning Phone
+// Numbers, Group IDs and Full JIDs Into The JIDs WhatsApp Expects
+package wajid
+
+import (
+	"errors"
+	"strings"
+)
+
+// WhatsApp JID Servers
+const (
+	ServerUser      = "s.whatsapp.net"
+	ServerGroup     = "g.us"
+	ServerBroadcast = "broadcast"
+
+	// serverLegacy is The Server Older Clients Use For Users
+	serverLegacy = "c.us"
+)
+
+// Phone Number Length Bounds in Digits, Country Code Included
+const (
+	phoneMinDigits = 7
+	phoneMaxDigits = 15
+)
+
+// DefaultCountryCode is Prepended to Local Phone Numbers, The Ones Starting
+// With a Trunk Prefix 0 or an Area Code in Parentheses. Local Numbers Are
+// Rejected While it is Empty
+var DefaultCountryCode string
+
+// Parse Errors
+var (
+	ErrEmpty         = errors.New("recipient is empty")
+	ErrInvalidPhone  = errors.New("recipient is not a valid phone number")
+	ErrInvalidGroup  = errors.New("recipient is not a valid group id")
+	ErrInvalidServer = errors.New("recipient has an unknown jid server")
+	ErrNoCountryCode = errors.New("recipient is a local phone number but no default country code is configured")
+)
+
+// JID Struct Describing a WhatsApp User, Group or Broadcast List
+type JID struct {
+	User   string
+	Server string
+}
+
+// String Method Returns The Full JID
+func (j JID) String() string {
+	return j.User + "@" + j.Server
+}
+
+// IsGroup Method Reports Whether The JID is a Group
+func (j JID) IsGroup() bool {
+	return j.Server == ServerGroup
+}
+
+// IsBroadcast Method Reports Whether The JID is a Broadcast List or The
+// Status Updates, Neither of Which Messages Can be Sent to Directly
+func (j JID) IsBroadcast() bool {
+	return j.Server == ServerBroadcast
+}
+
+// Parse Function Parses a Full JID, a Group ID or a Phone Number Using
+// DefaultCountryCode For Local Numbers
+func Parse(s string) (JID, error) {
+	return ParseWithCountryCode(s, DefaultCountryCode)
+}
+
+// ParseWithCountryCode Function Parses a Full JID, a Group ID or a Phone
+// Number Using countryCode For Local Numbers
+func ParseWithCountryCode(s string, countryCode string) (JID, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return JID{}, ErrEmpty
+	}
+
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		return parseFull(s[:i], strings.ToLower(s[i+1:]))
+	}
+
+	if isGroup(s) {
+		return JID{User: s, Server: ServerGroup}, nil
+	}
+
+	phone, err := normalizePhone(s, countryCode)
+	if err != nil {
+		return JID{}, err
+	}
+
+	return JID{User: phone, Server: ServerUser}, nil
+}
+
+// User Function Returns The User Part of a JID, or s Itself When it
+// Can Not be Parsed
+func User(s string) string {
+	j, err := Parse(s)
+	if err != nil {
+		return s
+	}
+
+	return j.User
+}
+
+// parseFull Function Validates The User Part of a Full JID by its Server
+func parseFull(user string, server string) (JID, error) {
+	switch server {
+	case ServerUser, serverLegacy:
+		// Device Suffixes Like 6281234567890:12 Address The Same User
+		if i := strings.Index(user, ":"); i >= 0 {
+			user = user[:i]
+		}
+
+		if !isDigits(user) || len(user) < phoneMinDigits || len(user) > phoneMaxDigits || user[0] == '0' {
+			return JID{}, ErrInvalidPhone
+		}
+
+		return JID{User: user, Server: ServerUser}, nil
+	case ServerGroup:
+		if !isGroup(user) && !isDigits(user) {
+			return JID{}, ErrInvalidGroup
+		}
+
+		return JID{User: user, Server: ServerGroup}, nil
+	case ServerBroadcast:
+		if user != "status" && !isDigits(user) {
+			return JID{}, ErrInvalidPhone
+		}
+
+		return JID{User: user, Server: ServerBroadcast}, nil
+	}
+
+	return JID{}, ErrInvalidServer
+}
+
+// isGroup Function Reports Whether s Looks Like a Group ID, The Creator's
+// Phone Number and The Unix Time of Creation Joined by a Dash
+func isGroup(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return false
+	}
+
+	return isDigits(parts[0]) && len(parts[0]) >= phoneMinDigits && isDigits(parts[1]) && len(parts[1]) == 10
+}
+
+// normalizePhone Function Returns The Digits of a Phone Number in
+// International Format Without Leading Plus
+func normalizePhone(s string, countryCode string) (string, error) {
+	international := true
+
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	default:
+		international = false
+	}
+
+	// A Trunk Prefix Written After The Country Code is Not Dialed From
+	// Abroad, as in +62 (0)812-3456-7890
+	if international {
+		s = strings.Replace(s, "(0)", "", 1)
+	}
+
+	local := !international && (strings.HasPrefix(s, "0") || strings.HasPrefix(s, "("))
+
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+
+		return r
+	}, s)
+
+	if !isDigits(digits) {
+		return "", ErrInvalidPhone
+	}
+
+	if local {
+		countryCode = strings.TrimPrefix(strings.TrimSpace(countryCode), "+")
+		if len(countryCode) == 0 {
+			return "", ErrNoCountryCode
+		}
+
+		if !isDigits(countryCode) {
+			return "", ErrInvalidPhone
+		}
+
+		digits = countryCode + strings.TrimPrefix(digits, "0")
+	}
+
+	if len(digits) < phoneMinDigits || len(digits) > phoneMaxDigits || digits[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+
+	return digits, nil
+}
+
+// isDigits Function Reports Whether s is a Non Empty String of ASCII Digits
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package wajid
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in          string
+		countryCode string
+		want        string
+		err         error
+	}{
+		// Phone Numbers
+		{"6281234567890", "62", "6281234567890@s.whatsapp.net", nil},
+		{"+62 812-3456-7890", "62", "6281234567890@s.whatsapp.net", nil},
+		{"+62 (0)812-3456-7890", "62", "6281234567890@s.whatsapp.net", nil},
+		{"+44 (0)20 7946 0958", "", "442079460958@s.whatsapp.net", nil},
+		{"0062 812 3456 7890", "", "6281234567890@s.whatsapp.net", nil},
+		{"0044 (0)20 7946 0958", "", "442079460958@s.whatsapp.net", nil},
+
+		// Local Phone Numbers
+		{"0812 3456 7890", "62", "6281234567890@s.whatsapp.net", nil},
+		{"(0)812 3456 7890", "62", "6281234567890@s.whatsapp.net", nil},
+		{"(555) 123-4567", "1", "15551234567@s.whatsapp.net", nil},
+		{"(555) 123-4567", "+1", "15551234567@s.whatsapp.net", nil},
+		{"0812 3456 7890", "", "", ErrNoCountryCode},
+		{"(555) 123-4567", "", "", ErrNoCountryCode},
+		{"(555) 123-4567", "one", "", ErrInvalidPhone},
+
+		// Full JIDs
+		{"6281234567890@c.us", "62", "6281234567890@s.whatsapp.net", nil},
+		{"6281234567890@S.WhatsApp.Net", "62", "6281234567890@s.whatsapp.net", nil},
+		{"6281234567890:12@s.whatsapp.net", "62", "6281234567890@s.whatsapp.net", nil},
+		{"6281234567890:3@c.us", "62", "6281234567890@s.whatsapp.net", nil},
+		{"0812345678@s.whatsapp.net", "62", "", ErrInvalidPhone},
+
+		// Groups
+		{"6281234567890-1562345678", "62", "6281234567890-1562345678@g.us", nil},
+		{"6281234567890-1562345678@g.us", "62", "6281234567890-1562345678@g.us", nil},
+		{"120363025246125486@g.us", "62", "120363025246125486@g.us", nil},
+		{"team@g.us", "62", "", ErrInvalidGroup},
+
+		// Broadcast Lists and Status Updates
+		{"status@broadcast", "62", "status@broadcast", nil},
+		{"1562345678@broadcast", "62", "1562345678@broadcast", nil},
+		{"everyone@broadcast", "62", "", ErrInvalidPhone},
+
+		// Garbage
+		{"", "62", "", ErrEmpty},
+		{"   ", "62", "", ErrEmpty},
+		{"not a number", "62", "", ErrInvalidPhone},
+		{"+62 812 abc", "62", "", ErrInvalidPhone},
+		{"+", "62", "", ErrInvalidPhone},
+		{"12345", "62", "", ErrInvalidPhone},
+		{"+62 812 3456 7890 1234", "62", "", ErrInvalidPhone},
+		{"6281234567890-15623", "62", "", ErrInvalidPhone},
+		{"@", "62", "", ErrInvalidServer},
+		{"6281234567890@", "62", "", ErrInvalidServer},
+		{"6281234567890@example.com", "62", "", ErrInvalidServer},
+	}
+
+	for _, test := range tests {
+		got, err := ParseWithCountryCode(test.in, test.countryCode)
+		if err != test.err {
+			t.Errorf("Parse(%q, %q) returned error %v, want %v", test.in, test.countryCode, err, test.err)
+			continue
+		}
+
+		if err == nil && got.String() != test.want {
+			t.Errorf("Parse(%q, %q) = %v, want %v", test.in, test.countryCode, got, test.want)
+		}
+	}
+}
+
+func TestJIDKind(t *testing.T) {
+	tests := []struct {
+		in        string
+		group     bool
+		broadcast bool
+	}{
+		{"6281234567890", false, false},
+		{"6281234567890-1562345678", true, false},
+		{"status@broadcast", false, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseWithCountryCode(test.in, "62")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.IsGroup() != test.group || got.IsBroadcast() != test.broadcast {
+			t.Errorf("%q is group %v and broadcast %v", test.in, got.IsGroup(), got.IsBroadcast())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package webhook Verifies The Signed Requests Posted by go-whatsapp-rest
+// to a Webhook so Receivers Can Reject Forged or Replayed Deliveries
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers Carried by Every Webhook Request
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix Names The Algorithm Used For The Signature Header
+const signaturePrefix = "sha256="
+
+// DefaultTolerance is How Far a Timestamp May Drift Before it is Rejected
+const DefaultTolerance = 5 * time.Minute
+
+// Verification Errors
+var (
+	ErrMissingHeader    = errors.New("webhook: missing signature headers")
+	ErrInvalidTimestamp = errors.New("webhook: invalid timestamp")
+	ErrExpiredTimestamp = errors.New("webhook: timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrReplayed         = errors.New("webhook: delivery already received")
+)
+
+// Sign Function Returns The Signature Header Value of a Delivery Sent at
+// timestamp, an HMAC-SHA256 Over The Delivery ID, The Unix Timestamp and
+// The Body Joined by Dots
+func Sign(secret string, id string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id))
+	mac.Write([]byte("."))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify Function Checks The Signature Headers of a Body Against secret and
+// Rejects Timestamps Further Than tolerance From Now
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	id := header.Get(HeaderID)
+	timestamp := header.Get(HeaderTimestamp)
+	signature := header.Get(HeaderSignature)
+
+	if len(id) == 0 || len(timestamp) == 0 || len(signature) == 0 {
+		return ErrMissingHeader
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	drift := time.Since(time.Unix(unix, 0))
+	if drift < 0 {
+		drift = -drift
+	}
+
+	if drift > tolerance {
+		return ErrExpiredTimestamp
+	}
+
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, id, unix, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Verifier Struct Verifies Requests and Remembers The IDs of Handled Ones for
+// as Long as Their Timestamp is Accepted, so a Captured Request Cannot be
+// Replayed. The ID is Signed Along With The Body so it Cannot be Changed Either
+type Verifier struct {
+	Secret    string
+	Tolerance time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewVerifier Function Returns a Verifier Using secret and DefaultTolerance
+func NewVerifier(secret string) *Verifier {
+	return &Verifier{
+		Secret:    secret,
+		Tolerance: DefaultTolerance,
+		seen:      make(map[string]time.Time),
+	}
+}
+
+// Verify Method Reads The Body of r, Checks its Signature and That its ID
+// Was Not Committed Before. The Body is Returned so The Caller Can Decode it.
+// Retries Keep Their ID, so ErrReplayed Should Still be Acknowledged With 2xx
+func (v *Verifier) Verify(r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Verify(v.Secret, r.Header, body, v.Tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.forget(time.Now())
+
+	if _, ok := v.seen[r.Header.Get(HeaderID)]; ok {
+		return nil, ErrReplayed
+	}
+
+	return body, nil
+}
+
+// Commit Method Remembers The ID of a Verified Request Once it Was Handled,
+// so Later Requests With The Same ID Get ErrReplayed. Requests That Failed
+// to be Handled Are Not Committed and Can Come Again With Their Retry
+func (v *Verifier) Commit(r *http.Request) {
+	now := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.forget(now)
+
+	v.seen[r.Header.Get(HeaderID)] = now.Add(2 * v.Tolerance)
+}
+
+// forget Method Drops IDs Whose Timestamp Would be Rejected Anyway
+// Must be Called With The Lock Held
+func (v *Verifier) forget(now time.Time) {
+	if v.seen == nil {
+		v.seen = make(map[string]time.Time)
+	}
+
+	for id, expires := range v.seen {
+		if now.After(expires) {
+			delete(v.seen, id)
+		}
+	}
+}
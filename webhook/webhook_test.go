@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRequest Function Builds a Delivery Request Signed With secret
+func testRequest(secret string, id string, body string) *http.Request {
+	timestamp := time.Now().Unix()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set(HeaderID, id)
+	r.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(HeaderSignature, Sign(secret, id, timestamp, []byte(body)))
+
+	return r
+}
+
+func TestVerifySignedID(t *testing.T) {
+	v := NewVerifier("secret")
+
+	r := testRequest("secret", "delivery", `{}`)
+	r.Header.Set(HeaderID, "forged")
+
+	_, err := v.Verify(r)
+	if err != ErrInvalidSignature {
+		t.Fatalf("request with a changed ID answered %v", err)
+	}
+
+	_, err = v.Verify(testRequest("other", "delivery", `{}`))
+	if err != ErrInvalidSignature {
+		t.Fatalf("request signed with another secret answered %v", err)
+	}
+}
+
+func TestVerifierCommit(t *testing.T) {
+	v := NewVerifier("secret")
+
+	// A Delivery That Was Not Committed Can Come Again With its Retry
+	for i := 0; i < 2; i++ {
+		body, err := v.Verify(testRequest("secret", "delivery", `{"n":1}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != `{"n":1}` {
+			t.Fatalf("unexpected body %s", body)
+		}
+	}
+
+	r := testRequest("secret", "delivery", `{"n":1}`)
+
+	_, err := v.Verify(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v.Commit(r)
+
+	_, err = v.Verify(testRequest("secret", "delivery", `{"n":1}`))
+	if err != ErrReplayed {
+		t.Fatalf("committed delivery answered %v", err)
+	}
+}